@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/workflowkit/issue-assistant/internal/helper"
+	"github.com/workflowkit/issue-assistant/pkg/ai"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+	"github.com/workflowkit/issue-assistant/pkg/redact"
+)
+
+// parseAIType parses an AI provider named by the variable name, accepting the aliases of ai.ToAIType
+func parseAIType(name, value string) ai.AIType {
+	aiType, err := ai.ToAIType(strings.TrimSpace(value))
+	if err != nil {
+		exitf(exitConfiguration, "%s must be one of 'openai', 'azure', 'claude', 'gemini', 'ollama' or 'llamacpp': %v", name, err)
+	}
+	return aiType
+}
+
+// aiConfig reads the API key and options of the AI provider from the environment
+func aiConfig(aiType ai.AIType) (string, []ai.Option) {
+	var apiKey string
+	var aiOptions []ai.Option
+	switch aiType {
+	case ai.AITypeOpenAI, ai.AITypeAzureOpenAI:
+		openAIKey := os.Getenv("OPENAI_API_KEY")
+		if openAIKey == "" {
+			exitf(exitConfiguration, "OPENAI_API_KEY is required when using OpenAI")
+		}
+		apiKey = openAIKey
+
+		baseURL := os.Getenv("OPENAI_BASE_URL")
+		if aiType == ai.AITypeAzureOpenAI && baseURL == "" {
+			exitf(exitConfiguration, "OPENAI_BASE_URL is required when using Azure OpenAI")
+		}
+		if baseURL != "" {
+			aiOptions = append(aiOptions, ai.WithEndpoint(baseURL))
+		}
+		if organization := os.Getenv("OPENAI_ORGANIZATION"); organization != "" {
+			aiOptions = append(aiOptions, ai.WithOrganization(organization))
+		}
+		if apiVersion := os.Getenv("OPENAI_API_VERSION"); apiVersion != "" {
+			aiOptions = append(aiOptions, ai.WithAPIVersion(apiVersion))
+		}
+		aiOptions = append(aiOptions, modelOptions("OPENAI")...)
+		if deployments := os.Getenv("AZURE_OPENAI_DEPLOYMENTS"); deployments != "" {
+			if !strings.Contains(deployments, "=") {
+				// A single deployment serves every model
+				aiOptions = append(aiOptions, ai.WithAzureDeployment("", strings.TrimSpace(deployments)))
+			} else {
+				mapping, err := parseMapping(deployments)
+				if err != nil {
+					exitf(exitConfiguration, "AZURE_OPENAI_DEPLOYMENTS is invalid: %v", err)
+				}
+				for model, deployment := range mapping {
+					aiOptions = append(aiOptions, ai.WithAzureDeployment(model, deployment))
+				}
+			}
+		}
+	case ai.AITypeClaude:
+		claudeKey := os.Getenv("CLAUDE_API_KEY")
+		if claudeKey == "" {
+			exitf(exitConfiguration, "CLAUDE_API_KEY is required when using Claude")
+		}
+		apiKey = claudeKey
+		aiOptions = append(aiOptions, modelOptions("CLAUDE")...)
+	case ai.AITypeGemini:
+		geminiKey := os.Getenv("GEMINI_API_KEY")
+		if geminiKey == "" {
+			exitf(exitConfiguration, "GEMINI_API_KEY is required when using Gemini")
+		}
+		apiKey = geminiKey
+		aiOptions = append(aiOptions, modelOptions("GEMINI")...)
+	case ai.AITypeOllama, ai.AITypeLlamaCpp:
+		// Local model servers usually run without authentication, the key is sent when set
+		apiKey = os.Getenv("LOCAL_AI_API_KEY")
+		if endpoint := os.Getenv("LOCAL_AI_ENDPOINT"); endpoint != "" {
+			aiOptions = append(aiOptions, ai.WithEndpoint(endpoint))
+		}
+		aiOptions = append(aiOptions, modelOptions("LOCAL_AI")...)
+	}
+
+	return apiKey, aiOptions
+}
+
+// modelOptions reads the model settings of a provider. The model is read from <PREFIX>_MODEL
+// and <PREFIX>_TASK_MODELS. Max tokens and temperature are read from <PREFIX>_MAX_TOKENS and
+// <PREFIX>_TEMPERATURE, falling back to AI_MAX_TOKENS and AI_TEMPERATURE, so a fallback provider
+// can use settings within its own limits.
+func modelOptions(prefix string) []ai.Option {
+	var models ai.ModelConfig
+	models.Model = os.Getenv(prefix + "_MODEL")
+	if name, maxTokens := providerSetting(prefix, "MAX_TOKENS"); maxTokens != "" {
+		models.MaxTokens = parseInt(name, maxTokens)
+	}
+	if name, temperature := providerSetting(prefix, "TEMPERATURE"); temperature != "" {
+		value := parseFloat(name, temperature)
+		models.Temperature = &value
+	}
+	opts := []ai.Option{ai.WithModelConfig(models)}
+
+	taskSettings := []struct {
+		name  string
+		apply func(*ai.ModelConfig, string)
+	}{
+		{prefix + "_TASK_MODELS", func(m *ai.ModelConfig, v string) { m.Model = v }},
+		{"AI_TASK_MAX_TOKENS", func(m *ai.ModelConfig, v string) { m.MaxTokens = parseInt("AI_TASK_MAX_TOKENS", v) }},
+		{"AI_TASK_TEMPERATURES", func(m *ai.ModelConfig, v string) {
+			value := parseFloat("AI_TASK_TEMPERATURES", v)
+			m.Temperature = &value
+		}},
+	}
+	for _, setting := range taskSettings {
+		mapping, err := parseMapping(os.Getenv(setting.name))
+		if err != nil {
+			exitf(exitConfiguration, "%s is invalid: %v", setting.name, err)
+		}
+		for name, value := range mapping {
+			task, err := ai.ParseTask(name)
+			if err != nil {
+				exitf(exitConfiguration, "%s is invalid: %v", setting.name, err)
+			}
+			var models ai.ModelConfig
+			setting.apply(&models, value)
+			opts = append(opts, ai.WithTaskModelConfig(task, models))
+		}
+	}
+
+	return opts
+}
+
+// providerSetting returns the name and value of <PREFIX>_<NAME>, or of AI_<NAME> when the
+// provider does not set it
+func providerSetting(prefix, name string) (string, string) {
+	if value := os.Getenv(prefix + "_" + name); value != "" {
+		return prefix + "_" + name, value
+	}
+	return "AI_" + name, os.Getenv("AI_" + name)
+}
+
+// newRedactor creates the redactor with the patterns of REDACT_PATTERNS, one regular
+// expression per line, and the secret file globs of SECRET_FILES
+func newRedactor() *redact.Redactor {
+	var opts []redact.Option
+	if patterns := os.Getenv("REDACT_PATTERNS"); patterns != "" {
+		var lines []string
+		for _, line := range strings.Split(patterns, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		opts = append(opts, redact.WithPatterns(lines...))
+	}
+	if files := os.Getenv("SECRET_FILES"); files != "" {
+		opts = append(opts, redact.WithSecretFiles(parseList(files)...))
+	}
+
+	redactor, err := redact.New(opts...)
+	if err != nil {
+		exitf(exitConfiguration, "failed to configure redaction: %v", err)
+	}
+	return redactor
+}
+
+// logOptions returns the logger options set by LOG_LEVEL and LOG_FORMAT
+func logOptions() []logger.Option {
+	var opts []logger.Option
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		level, err := logger.ParseLevel(value)
+		if err != nil {
+			exitf(exitConfiguration, "LOG_LEVEL is invalid: %v", err)
+		}
+		opts = append(opts, logger.WithLevel(level))
+	}
+	if value := os.Getenv("LOG_FORMAT"); value != "" {
+		format, err := logger.ParseFormat(value)
+		if err != nil {
+			exitf(exitConfiguration, "LOG_FORMAT is invalid: %v", err)
+		}
+		opts = append(opts, logger.WithFormat(format))
+	}
+	return opts
+}
+
+func parseInt(name, value string) int {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		exitf(exitConfiguration, "%s must be a number: %v", name, err)
+	}
+	return i
+}
+
+func parseFloat(name, value string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		exitf(exitConfiguration, "%s must be a number: %v", name, err)
+	}
+	return f
+}
+
+// parsePrices parses model prices given as "model=prompt/completion" in USD per million tokens
+func parsePrices(s string) map[string]ai.Price {
+	mapping, err := parseMapping(s)
+	if err != nil {
+		exitf(exitConfiguration, "AI_PRICES is invalid: %v", err)
+	}
+	prices := make(map[string]ai.Price, len(mapping))
+	for model, value := range mapping {
+		price, err := ai.ParsePrice(value)
+		if err != nil {
+			exitf(exitConfiguration, "AI_PRICES is invalid: %v", err)
+		}
+		prices[model] = price
+	}
+	return prices
+}
+
+// parseTimeouts parses feature time limits given as "feature=duration", e.g. "comment=10m,label=1m"
+func parseTimeouts(s string) map[helper.Feature]time.Duration {
+	mapping, err := parseMapping(s)
+	if err != nil {
+		exitf(exitConfiguration, "FEATURE_TIMEOUTS is invalid: %v", err)
+	}
+	timeouts := make(map[helper.Feature]time.Duration, len(mapping))
+	for feature, value := range mapping {
+		timeouts[helper.Feature(feature)] = parseDuration("FEATURE_TIMEOUTS", value)
+	}
+	return timeouts
+}
+
+func parseDuration(name, value string) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		exitf(exitConfiguration, "%s must be a duration such as 5m: %v", name, err)
+	}
+	return d
+}
+
+// policyConfig reads the rules deciding which issues are processed
+func policyConfig() helper.PolicyConfig {
+	config := helper.PolicyConfig{
+		AllowBots:          os.Getenv("POLICY_ALLOW_BOTS") == "true",
+		AuthorAssociations: parseList(strings.ToUpper(os.Getenv("POLICY_AUTHOR_ASSOCIATIONS"))),
+		DenyAuthors:        parseList(os.Getenv("POLICY_DENY_AUTHORS")),
+		RequireLabels:      parseList(os.Getenv("POLICY_REQUIRE_LABELS")),
+		SkipLabels:         parseList(os.Getenv("POLICY_SKIP_LABELS")),
+		AllowForks:         os.Getenv("POLICY_ALLOW_FORKS") == "true",
+		SkipOnError:        os.Getenv("POLICY_SKIP_ON_ERROR") == "true",
+	}
+	if days := os.Getenv("POLICY_MIN_ACCOUNT_AGE_DAYS"); days != "" {
+		config.MinAccountAgeDays = parseInt("POLICY_MIN_ACCOUNT_AGE_DAYS", days)
+	}
+	if limit := os.Getenv("POLICY_MAX_ISSUES_OPENED_PER_DAY"); limit != "" {
+		config.MaxIssuesOpenedPerDay = parseInt("POLICY_MAX_ISSUES_OPENED_PER_DAY", limit)
+	}
+	return config
+}
+
+// parseList parses values separated by commas or newlines, labels may contain spaces
+func parseList(s string) []string {
+	var values []string
+	for _, value := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// parseMapping parses "key=value" pairs separated by commas or newlines
+func parseMapping(s string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, pair := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected key=value, got %q", pair)
+		}
+		mapping[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return mapping, nil
+}
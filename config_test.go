@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/workflowkit/issue-assistant/pkg/ai"
+)
+
+func TestModelOptionsPerProvider(t *testing.T) {
+	t.Setenv("AI_TEMPERATURE", "1.5")
+	t.Setenv("AI_MAX_TOKENS", "3000")
+
+	if err := ai.Validate(ai.AITypeOpenAI, modelOptions("OPENAI")...); err != nil {
+		t.Errorf("OpenAI with the global temperature: %v", err)
+	}
+	if err := ai.Validate(ai.AITypeClaude, modelOptions("CLAUDE")...); err == nil {
+		t.Error("Claude accepted the global temperature 1.5")
+	}
+
+	// The provider setting takes precedence over the global one
+	t.Setenv("CLAUDE_TEMPERATURE", "0.7")
+	if err := ai.Validate(ai.AITypeClaude, modelOptions("CLAUDE")...); err != nil {
+		t.Errorf("Claude with its own temperature: %v", err)
+	}
+}
+
+func TestProviderSetting(t *testing.T) {
+	t.Setenv("AI_MAX_TOKENS", "3000")
+	t.Setenv("GEMINI_MAX_TOKENS", "8000")
+
+	tests := []struct {
+		prefix    string
+		wantName  string
+		wantValue string
+	}{
+		{"GEMINI", "GEMINI_MAX_TOKENS", "8000"},
+		{"OPENAI", "AI_MAX_TOKENS", "3000"},
+	}
+	for _, tt := range tests {
+		if name, value := providerSetting(tt.prefix, "MAX_TOKENS"); name != tt.wantName || value != tt.wantValue {
+			t.Errorf("providerSetting(%s) = %s=%s, want %s=%s", tt.prefix, name, value, tt.wantName, tt.wantValue)
+		}
+	}
+}
+
+func TestParseAIType(t *testing.T) {
+	tests := map[string]ai.AIType{
+		"openai":       ai.AITypeOpenAI,
+		"Azure":        ai.AITypeAzureOpenAI,
+		"azure-openai": ai.AITypeAzureOpenAI,
+		"Claude":       ai.AITypeClaude,
+		" gemini ":     ai.AITypeGemini,
+		"llama.cpp":    ai.AITypeLlamaCpp,
+		"Ollama":       ai.AITypeOllama,
+	}
+	for value, want := range tests {
+		if got := parseAIType("AI_TYPE", value); got != want {
+			t.Errorf("parseAIType(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestAIConfigAlias(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "test-key")
+	t.Setenv("OPENAI_BASE_URL", "https://example.openai.azure.com")
+
+	apiKey, opts := aiConfig(parseAIType("AI_TYPE", "Azure"))
+	if apiKey != "test-key" {
+		t.Errorf("api key = %q, want the OpenAI key", apiKey)
+	}
+	if err := ai.Validate(ai.AITypeAzureOpenAI, opts...); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
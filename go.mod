@@ -8,6 +8,8 @@ require (
 	github.com/sashabaranov/go-openai v1.36.1
 	go.uber.org/zap v1.27.0
 	golang.org/x/oauth2 v0.25.0
+	golang.org/x/sync v0.9.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
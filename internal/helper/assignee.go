@@ -0,0 +1,214 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/workflowkit/issue-assistant/pkg/codeowners"
+	pkggithub "github.com/workflowkit/issue-assistant/pkg/github"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// maxPromptPaths limits the number of repository paths sent to the AI
+const maxPromptPaths = 1500
+
+// commitsPerFile is the number of recent commits inspected per relevant file
+const commitsPerFile = 20
+
+// Weights of the ownership signals when ranking suggestions
+const (
+	codeOwnerWeight = 3
+	commitWeight    = 1
+)
+
+// assigneeSuggestion is a user or team suggested to look at the issue
+type assigneeSuggestion struct {
+	login   string
+	score   int
+	owned   []string
+	commits int
+}
+
+// processAssignees handles suggested assignees feature
+func (h *Helper) processAssignees(ctx context.Context, event *GitHubEvent) error {
+	owner := event.Repository.Owner.Login
+	repo := event.Repository.Name
+
+	paths, err := h.repositoryTree(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	paths = promptPaths(paths)
+	files, err := h.aiService.AnalyzeRelevantFiles(ctx, event.Issue.Title, event.Issue.Body, paths)
+	if err != nil {
+		return fmt.Errorf("failed to analyze relevant files: %w", err)
+	}
+
+	if len(files) == 0 {
+		return skip("no relevant files found")
+	}
+
+	suggestions, err := h.suggestAssignees(ctx, event, files)
+	if err != nil {
+		return fmt.Errorf("failed to suggest assignees: %w", err)
+	}
+
+	if len(suggestions) == 0 {
+		return skip("no owners found for relevant files")
+	}
+
+	if h.assigneeConfig.Assign {
+		for _, suggestion := range suggestions {
+			// Teams cannot be assigned, pick the best ranked user
+			if strings.Contains(suggestion.login, "/") {
+				continue
+			}
+			if err := h.githubClient.AddAssignees(ctx, owner, repo, event.Issue.Number, []string{suggestion.login}); err != nil {
+				return fmt.Errorf("failed to assign %s: %w", suggestion.login, err)
+			}
+			logger.FromContext(ctx).Infof("assigned %s to issue", suggestion.login)
+			break
+		}
+	}
+
+	if err := h.createComment(ctx, event, h.formatAssigneeComment(files, suggestions)); err != nil {
+		return fmt.Errorf("failed to create assignee comment: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("successfully added suggested assignees comment")
+
+	return nil
+}
+
+// suggestAssignees ranks the code owners and recent commit authors of the relevant files
+func (h *Helper) suggestAssignees(ctx context.Context, event *GitHubEvent, files []string) ([]assigneeSuggestion, error) {
+	owner := event.Repository.Owner.Login
+	repo := event.Repository.Name
+
+	var rules codeowners.Ruleset
+	for _, location := range codeowners.Locations {
+		content, found, err := h.githubClient.GetFile(ctx, owner, repo, location)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			rules = codeowners.Parse(content)
+			logger.FromContext(ctx).Infof("using code owners from %s", location)
+			break
+		}
+	}
+
+	excluded := map[string]bool{strings.ToLower(event.Issue.User.Login): true}
+	for _, login := range h.assigneeConfig.OptOut {
+		excluded[strings.ToLower(strings.TrimPrefix(login, "@"))] = true
+	}
+
+	candidates := make(map[string]*assigneeSuggestion)
+	candidate := func(login string) *assigneeSuggestion {
+		login = strings.TrimPrefix(login, "@")
+		// Email owners cannot be mentioned and bots should not be
+		if login == "" || strings.Contains(login, "@") || strings.HasSuffix(login, "[bot]") || excluded[strings.ToLower(login)] {
+			return nil
+		}
+		if _, ok := candidates[login]; !ok {
+			candidates[login] = &assigneeSuggestion{login: login}
+		}
+		return candidates[login]
+	}
+
+	since := time.Now().AddDate(0, 0, -h.assigneeConfig.CommitDays)
+	for _, file := range files {
+		for _, login := range rules.Owners(file) {
+			if c := candidate(login); c != nil {
+				c.score += codeOwnerWeight
+				c.owned = append(c.owned, file)
+			}
+		}
+
+		authors, err := h.githubClient.GetCommitAuthors(ctx, owner, repo, file, since, commitsPerFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, login := range authors {
+			if c := candidate(login); c != nil {
+				c.score += commitWeight
+				c.commits++
+			}
+		}
+	}
+
+	suggestions := make([]assigneeSuggestion, 0, len(candidates))
+	for _, c := range candidates {
+		suggestions = append(suggestions, *c)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].score != suggestions[j].score {
+			return suggestions[i].score > suggestions[j].score
+		}
+		return suggestions[i].login < suggestions[j].login
+	})
+	if len(suggestions) > h.assigneeConfig.MaxSuggestions {
+		suggestions = suggestions[:h.assigneeConfig.MaxSuggestions]
+	}
+
+	return suggestions, nil
+}
+
+// formatAssigneeComment formats the suggested assignees as a GitHub issue comment
+func (h *Helper) formatAssigneeComment(files []string, suggestions []assigneeSuggestion) string {
+	var people strings.Builder
+	for _, suggestion := range suggestions {
+		name := fmt.Sprintf("`%s`", suggestion.login)
+		if h.assigneeConfig.Mention {
+			name = "@" + suggestion.login
+		}
+
+		var reasons []string
+		if len(suggestion.owned) > 0 {
+			reasons = append(reasons, fmt.Sprintf("code owner of %d relevant file(s)", len(suggestion.owned)))
+		}
+		if suggestion.commits > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d recent commit(s) to relevant files", suggestion.commits))
+		}
+		people.WriteString(fmt.Sprintf("- %s: %s\n", name, strings.Join(reasons, ", ")))
+	}
+
+	var paths strings.Builder
+	for _, file := range files {
+		paths.WriteString(fmt.Sprintf("- `%s`\n", file))
+	}
+
+	return fmt.Sprintf(`👥 AI Assignee Assistant
+
+Based on the files most relevant to this issue, the following people may be best placed to look at it:
+
+%s
+**Relevant files:**
+%s
+---
+_These suggestions were made by [Issue Assistant](https://github.com/workflowkit/issue-assistant) from CODEOWNERS and recent commit history. If you have any questions, please contact the repository maintainers._`,
+		people.String(),
+		paths.String(),
+	)
+}
+
+// promptPaths drops excluded paths and limits the number of paths sent to the AI
+func promptPaths(paths []string) []string {
+	excluded := pkggithub.DefaultFileFilter().ExcludedPaths
+
+	var result []string
+	for _, p := range paths {
+		if isExcludedPath(p, excluded) {
+			continue
+		}
+		result = append(result, p)
+		if len(result) == maxPromptPaths {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pkggithub "github.com/workflowkit/issue-assistant/pkg/github"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+	"github.com/workflowkit/issue-assistant/pkg/similarity"
+)
+
+// searchKeywords is the number of title keywords used to search for candidates.
+// GitHub search allows at most five OR operators per query.
+const searchKeywords = 5
+
+// searchLimit is the number of issues fetched per search query
+const searchLimit = 30
+
+// processDuplicates handles duplicate issue detection feature
+func (h *Helper) processDuplicates(ctx context.Context, event *GitHubEvent) error {
+	candidates, err := h.findDuplicateCandidates(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to find duplicate candidates: %w", err)
+	}
+
+	if len(candidates) == 0 {
+		return skip("no similar issues found")
+	}
+
+	analysis, err := h.aiService.AnalyzeDuplicates(ctx, event.Issue.Title, event.Issue.Body, candidates)
+	if err != nil {
+		return fmt.Errorf("failed to analyze duplicates: %w", err)
+	}
+
+	if len(analysis.Duplicates) == 0 {
+		return skip("no duplicates confirmed")
+	}
+
+	sort.SliceStable(analysis.Duplicates, func(i, j int) bool {
+		return analysis.Duplicates[i].Confidence > analysis.Duplicates[j].Confidence
+	})
+
+	comment := h.formatDuplicateComment(analysis, candidates)
+	if err := h.createComment(ctx, event, comment); err != nil {
+		return fmt.Errorf("failed to create duplicate comment: %w", err)
+	}
+
+	top := analysis.Duplicates[0]
+	if h.duplicateConfig.Label != "" && top.Confidence >= h.duplicateConfig.LabelThreshold {
+		if err := h.githubClient.AddLabelsToIssue(ctx,
+			event.Repository.Owner.Login,
+			event.Repository.Name,
+			event.Issue.Number,
+			[]string{h.duplicateConfig.Label}); err != nil {
+			return fmt.Errorf("failed to add duplicate label: %w", err)
+		}
+		logger.FromContext(ctx).Infof("added %s label, issue #%d matched with confidence %.2f", h.duplicateConfig.Label, top.Number, top.Confidence)
+	}
+
+	logger.FromContext(ctx).Info("successfully added duplicate detection comment")
+
+	return nil
+}
+
+// findDuplicateCandidates searches open and recently closed issues and ranks them by local similarity
+func (h *Helper) findDuplicateCandidates(ctx context.Context, event *GitHubEvent) ([]pkggithub.DuplicateCandidate, error) {
+	keywords := similarity.Keywords(event.Issue.Title, searchKeywords)
+	if len(keywords) == 0 {
+		return nil, nil
+	}
+	terms := strings.Join(keywords, " OR ")
+
+	closedSince := time.Now().AddDate(0, 0, -h.duplicateConfig.ClosedWithinDays).Format("2006-01-02")
+	queries := []string{
+		fmt.Sprintf("is:open %s in:title,body", terms),
+		fmt.Sprintf("is:closed closed:>=%s %s in:title,body", closedSince, terms),
+	}
+
+	seen := make(map[int]bool)
+	var candidates []pkggithub.DuplicateCandidate
+	for _, query := range queries {
+		issues, err := h.githubClient.SearchIssues(ctx,
+			event.Repository.Owner.Login,
+			event.Repository.Name,
+			query,
+			searchLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			if issue.Number == event.Issue.Number || seen[issue.Number] {
+				continue
+			}
+			seen[issue.Number] = true
+
+			score := similarity.IssueScore(event.Issue.Title, event.Issue.Body, issue.Title, issue.Body)
+			if score < h.duplicateConfig.MinSimilarity {
+				continue
+			}
+			candidates = append(candidates, pkggithub.DuplicateCandidate{Issue: issue, Similarity: score})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+	if len(candidates) > h.duplicateConfig.MaxCandidates {
+		candidates = candidates[:h.duplicateConfig.MaxCandidates]
+	}
+
+	logger.FromContext(ctx).Infof("found %d duplicate candidates", len(candidates))
+	return candidates, nil
+}
+
+// formatDuplicateComment formats the confirmed duplicates as a GitHub issue comment
+func (h *Helper) formatDuplicateComment(analysis pkggithub.DuplicateAnalysis, candidates []pkggithub.DuplicateCandidate) string {
+	similarities := make(map[int]pkggithub.DuplicateCandidate, len(candidates))
+	for _, candidate := range candidates {
+		similarities[candidate.Issue.Number] = candidate
+	}
+
+	var rows strings.Builder
+	for _, match := range analysis.Duplicates {
+		candidate := similarities[match.Number]
+		rows.WriteString(fmt.Sprintf("| #%d | %s | %.0f%% | %.0f%% | %s |\n",
+			match.Number,
+			candidate.Issue.State,
+			candidate.Similarity*100,
+			match.Confidence*100,
+			strings.ReplaceAll(strings.ReplaceAll(sanitizeModelText(match.Reason), "|", "\\|"), "\n", " ")))
+	}
+
+	return fmt.Sprintf(`🔁 AI Duplicate Detector
+
+This issue looks similar to the following existing issues:
+
+| Issue | State | Similarity | Confidence | Reason |
+|-------|-------|------------|------------|--------|
+%s
+**Explanation:**
+%s
+
+---
+_This duplicate analysis was performed by [Issue Assistant](https://github.com/workflowkit/issue-assistant). If you have any questions, please contact the repository maintainers._`,
+		rows.String(),
+		sanitizeModelText(analysis.Explanation),
+	)
+}
@@ -0,0 +1,22 @@
+package helper
+
+import (
+	"errors"
+
+	"github.com/workflowkit/issue-assistant/pkg/ai"
+	pkggithub "github.com/workflowkit/issue-assistant/pkg/github"
+)
+
+var (
+	// ErrMalformedEvent is returned when the GitHub event cannot be read or is not an issue event
+	ErrMalformedEvent = errors.New("malformed event")
+	// ErrAuthentication is returned when GitHub or the AI provider rejected the credentials
+	ErrAuthentication = errors.New("authentication failed")
+	// ErrFeaturesFailed is returned when feature failures fail the run under the failure policy
+	ErrFeaturesFailed = errors.New("features failed")
+)
+
+// isAuthError reports whether err was caused by rejected GitHub or AI provider credentials
+func isAuthError(err error) bool {
+	return errors.Is(err, ai.ErrAuthentication) || pkggithub.IsAuthenticationError(err)
+}
@@ -6,26 +6,54 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/workflowkit/issue-assistant/pkg/ai"
 	pkggithub "github.com/workflowkit/issue-assistant/pkg/github"
 	"github.com/workflowkit/issue-assistant/pkg/logger"
+	"github.com/workflowkit/issue-assistant/pkg/redact"
+	"golang.org/x/sync/errgroup"
 )
 
 // Feature represents an AI assistant feature
 type Feature string
 
 const (
-	FeatureComment Feature = "comment" // AI analysis comments
-	FeatureLabel   Feature = "label"   // Label suggestions
+	FeatureComment     Feature = "comment"      // AI analysis comments
+	FeatureLabel       Feature = "label"        // Label suggestions
+	FeatureDuplicate   Feature = "duplicate"    // Duplicate issue detection
+	FeatureMissingInfo Feature = "missing-info" // Missing template information requests
+	FeatureTriage      Feature = "triage"       // Structured triage
+	FeatureAssignee    Feature = "assignee"     // Suggested assignees
+	FeatureProject     Feature = "project"      // Project and milestone placement
 )
 
+// allFeatures are the supported features
+var allFeatures = []Feature{FeatureComment, FeatureLabel, FeatureDuplicate, FeatureMissingInfo, FeatureTriage, FeatureAssignee, FeatureProject}
+
 // Helper is the main struct that holds the clients and services
 type Helper struct {
-	githubEventPath string
-	githubClient    *pkggithub.Client
-	aiService       ai.AIService
-	features        []Feature
+	githubEventPath   string
+	githubClient      pkggithub.GitHubService
+	githubMetrics     *pkggithub.MetricsService
+	dryRun            bool
+	aiService         ai.AIService
+	aiProviders       []ai.Provider
+	aiModels          []string
+	prompts           *ai.PromptRegistry
+	accountant        *ai.Accountant
+	redactor          *redact.Redactor
+	features          []Feature
+	duplicateConfig   DuplicateConfig
+	missingInfoConfig MissingInfoConfig
+	policyConfig      PolicyConfig
+	injectionConfig   InjectionConfig
+	triageConfig      TriageConfig
+	assigneeConfig    AssigneeConfig
+	projectConfig     ProjectConfig
+	failurePolicy     FailurePolicy
+	timeoutConfig     TimeoutConfig
 }
 
 // Option is a function type that modifies Helper
@@ -33,7 +61,18 @@ type Option func(*Helper) error
 
 // NewHelper creates a new Helper instance with the given options
 func NewHelper(opts ...Option) (*Helper, error) {
-	h := &Helper{}
+	h := &Helper{
+		duplicateConfig:   DefaultDuplicateConfig(),
+		missingInfoConfig: DefaultMissingInfoConfig(),
+		injectionConfig:   DefaultInjectionConfig(),
+		triageConfig:      DefaultTriageConfig(),
+		assigneeConfig:    DefaultAssigneeConfig(),
+		failurePolicy:     FailOnAny,
+		timeoutConfig:     DefaultTimeoutConfig(),
+		prompts:           ai.NewPromptRegistry(),
+		accountant:        ai.NewAccountant(),
+		redactor:          redact.Default(),
+	}
 
 	for _, opt := range opts {
 		if err := opt(h); err != nil {
@@ -41,6 +80,15 @@ func NewHelper(opts ...Option) (*Helper, error) {
 		}
 	}
 
+	if len(h.aiProviders) > 0 {
+		h.aiService = ai.NewFallbackService(h.aiProviders...)
+	}
+	h.prompts.SetRedaction(h.redactor.Redact)
+
+	if h.githubClient != nil {
+		h.githubClient = h.decorateGitHubClient(h.githubClient)
+	}
+
 	if err := h.validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
@@ -49,30 +97,123 @@ func NewHelper(opts ...Option) (*Helper, error) {
 }
 
 // WithGitHubClient sets the GitHub client
-func WithGitHubClient(token string) Option {
+func WithGitHubClient(token string, opts ...pkggithub.ClientOption) Option {
 	return func(h *Helper) error {
 		if token == "" {
 			return errors.New("github token cannot be empty")
 		}
-		h.githubClient = pkggithub.NewClient(token)
+		client, err := pkggithub.NewClient(token, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create github client: %w", err)
+		}
+		h.githubClient = client
+		return nil
+	}
+}
+
+// WithGitHubService sets an already constructed GitHub service, e.g. a client of a fake server
+func WithGitHubService(service pkggithub.GitHubService) Option {
+	return func(h *Helper) error {
+		if service == nil {
+			return errors.New("github service cannot be nil")
+		}
+		h.githubClient = service
+		return nil
+	}
+}
+
+// WithRedactor sets the redactor removing secrets and personal data from prompts
+// and excluding secret files from the repository content sent to the AI
+func WithRedactor(redactor *redact.Redactor) Option {
+	return func(h *Helper) error {
+		if redactor == nil {
+			return errors.New("redactor cannot be nil")
+		}
+		h.redactor = redactor
+		return nil
+	}
+}
+
+// WithDryRun logs the changes to issues and projects instead of making them
+func WithDryRun(dryRun bool) Option {
+	return func(h *Helper) error {
+		h.dryRun = dryRun
+		return nil
+	}
+}
+
+// decorateGitHubClient layers metrics, the read cache and dry run around the GitHub service.
+// Metrics are innermost so they count the requests actually sent.
+func (h *Helper) decorateGitHubClient(service pkggithub.GitHubService) pkggithub.GitHubService {
+	h.githubMetrics = pkggithub.NewMetricsService(service)
+	service = pkggithub.NewCachingService(h.githubMetrics)
+	if h.dryRun {
+		logger.Log.Info("dry run enabled, no changes will be made")
+		service = pkggithub.NewDryRunService(service)
+	}
+	return service
+}
+
+// WithAIService sets the primary AI service
+func WithAIService(aiType string, apiKey string, opts ...ai.Option) Option {
+	return func(h *Helper) error {
+		provider, err := h.newAIProvider(aiType, apiKey, opts...)
+		if err != nil {
+			return err
+		}
+		h.aiProviders = append([]ai.Provider{provider}, h.aiProviders...)
 		return nil
 	}
 }
 
-// WithAIService sets the AI service
-func WithAIService(aiType string, apiKey string) Option {
+// WithAIProvider sets an already constructed AI service as the primary service,
+// e.g. a scripted mock to run the helper offline
+func WithAIProvider(name string, service ai.AIService) Option {
 	return func(h *Helper) error {
-		if aiType == "" {
-			return errors.New("ai type cannot be empty")
+		if service == nil {
+			return errors.New("ai service cannot be nil")
 		}
-		if apiKey == "" {
-			return errors.New("ai api key cannot be empty")
+		h.aiProviders = append([]ai.Provider{{Name: name, Service: service}}, h.aiProviders...)
+		return nil
+	}
+}
+
+// WithFallbackAIService adds an AI service that is tried when the previous services fail
+func WithFallbackAIService(aiType string, apiKey string, opts ...ai.Option) Option {
+	return func(h *Helper) error {
+		provider, err := h.newAIProvider(aiType, apiKey, opts...)
+		if err != nil {
+			return fmt.Errorf("fallback %s: %w", aiType, err)
 		}
-		h.aiService = ai.NewAIService(ai.ToAIType(aiType), apiKey)
+		h.aiProviders = append(h.aiProviders, provider)
 		return nil
 	}
 }
 
+// newAIProvider creates a provider that renders the prompts of the helper
+func (h *Helper) newAIProvider(aiType string, apiKey string, opts ...ai.Option) (ai.Provider, error) {
+	if aiType == "" {
+		return ai.Provider{}, errors.New("ai type cannot be empty")
+	}
+	t, err := ai.ToAIType(aiType)
+	if err != nil {
+		return ai.Provider{}, err
+	}
+	if apiKey == "" && t.RequiresAPIKey() {
+		return ai.Provider{}, errors.New("ai api key cannot be empty")
+	}
+	if err := ai.Validate(t, opts...); err != nil {
+		return ai.Provider{}, fmt.Errorf("invalid ai configuration: %w", err)
+	}
+	opts = append(opts, ai.WithPromptRegistry(h.prompts), ai.WithAccountant(h.accountant))
+	service, err := ai.NewAIService(t, apiKey, opts...)
+	if err != nil {
+		return ai.Provider{}, err
+	}
+	h.aiModels = append(h.aiModels, ai.Models(t, opts...)...)
+	return ai.Provider{Name: string(t), Service: service}, nil
+}
+
 // WithGitHubEventPath sets the GitHub event path
 func WithGitHubEventPath(path string) Option {
 	return func(h *Helper) error {
@@ -88,17 +229,159 @@ func WithGitHubEventPath(path string) Option {
 func WithFeatures(features []Feature) Option {
 	return func(h *Helper) error {
 		for _, f := range features {
-			switch f {
-			case FeatureComment, FeatureLabel:
-				h.features = append(h.features, f)
-			default:
+			if !slices.Contains(allFeatures, f) {
 				return fmt.Errorf("unknown feature: %s", f)
 			}
+			h.features = append(h.features, f)
 		}
 		return nil
 	}
 }
 
+// WithFailurePolicy sets which feature failures fail the run
+func WithFailurePolicy(policy FailurePolicy) Option {
+	return func(h *Helper) error {
+		switch policy {
+		case FailOnAny, FailOnAll, FailOnNever:
+			h.failurePolicy = policy
+			return nil
+		default:
+			return fmt.Errorf("unknown failure policy: %s", policy)
+		}
+	}
+}
+
+// WithTimeoutConfig sets the time limits of features
+func WithTimeoutConfig(config TimeoutConfig) Option {
+	return func(h *Helper) error {
+		if config.Default <= 0 {
+			return errors.New("feature timeout must be positive")
+		}
+		for feature, timeout := range config.Features {
+			if !slices.Contains(allFeatures, feature) {
+				return fmt.Errorf("unknown feature: %s", feature)
+			}
+			if timeout <= 0 {
+				return fmt.Errorf("timeout of feature %s must be positive", feature)
+			}
+		}
+		h.timeoutConfig = config
+		return nil
+	}
+}
+
+// WithDuplicateConfig sets the duplicate detection configuration
+func WithDuplicateConfig(config DuplicateConfig) Option {
+	return func(h *Helper) error {
+		if config.MaxCandidates <= 0 {
+			return errors.New("duplicate max candidates must be positive")
+		}
+		if config.LabelThreshold < 0 || config.LabelThreshold > 1 {
+			return errors.New("duplicate label threshold must be between 0 and 1")
+		}
+		h.duplicateConfig = config
+		return nil
+	}
+}
+
+// WithCostConfig sets the model prices and the cost budget of a run
+func WithCostConfig(config CostConfig) Option {
+	return func(h *Helper) error {
+		if config.Budget < 0 {
+			return errors.New("ai budget cannot be negative")
+		}
+		for model, price := range config.Prices {
+			h.accountant.SetPrice(model, price)
+		}
+		if config.DefaultPrice != nil {
+			h.accountant.SetDefaultPrice(*config.DefaultPrice)
+		}
+		h.accountant.SetBudget(config.Budget)
+		return nil
+	}
+}
+
+// WithMissingInfoConfig sets the missing information configuration
+func WithMissingInfoConfig(config MissingInfoConfig) Option {
+	return func(h *Helper) error {
+		h.missingInfoConfig = config
+		return nil
+	}
+}
+
+// WithPolicyConfig sets the rules deciding which issues are processed
+func WithPolicyConfig(config PolicyConfig) Option {
+	return func(h *Helper) error {
+		if config.MinAccountAgeDays < 0 || config.MaxIssuesOpenedPerDay < 0 {
+			return errors.New("policy limits cannot be negative")
+		}
+		h.policyConfig = config
+		return nil
+	}
+}
+
+// WithInjectionConfig sets the prompt injection detection configuration
+func WithInjectionConfig(config InjectionConfig) Option {
+	return func(h *Helper) error {
+		h.injectionConfig = config
+		return nil
+	}
+}
+
+// WithTriageConfig sets the structured triage configuration
+func WithTriageConfig(config TriageConfig) Option {
+	return func(h *Helper) error {
+		if config.ComponentDepth <= 0 {
+			return errors.New("triage component depth must be positive")
+		}
+		for key := range config.Labels {
+			field, _, ok := strings.Cut(key, ":")
+			if !ok || !slices.Contains(triageFields, field) {
+				return fmt.Errorf("invalid triage label key %q, expected field:value with field one of %s", key, strings.Join(triageFields, ", "))
+			}
+		}
+		h.triageConfig = config
+		return nil
+	}
+}
+
+// WithAssigneeConfig sets the suggested assignees configuration
+func WithAssigneeConfig(config AssigneeConfig) Option {
+	return func(h *Helper) error {
+		if config.MaxSuggestions <= 0 {
+			return errors.New("assignee max suggestions must be positive")
+		}
+		if config.CommitDays <= 0 {
+			return errors.New("assignee commit days must be positive")
+		}
+		h.assigneeConfig = config
+		return nil
+	}
+}
+
+// WithProjectConfig sets the project and milestone placement configuration
+func WithProjectConfig(config ProjectConfig) Option {
+	return func(h *Helper) error {
+		if config.Number < 0 {
+			return errors.New("project number cannot be negative")
+		}
+		for name, source := range config.Fields {
+			if source != currentIteration && source != nextIteration && !slices.Contains(triageFields, source) {
+				return fmt.Errorf("invalid source %q of project field %s, expected %s, %s or %s",
+					source, name, strings.Join(triageFields, ", "), currentIteration, nextIteration)
+			}
+		}
+		for key := range config.Milestones {
+			field, _, ok := strings.Cut(key, ":")
+			if !ok || !slices.Contains(triageFields, field) {
+				return fmt.Errorf("invalid milestone rule %q, expected field:value with field one of %s", key, strings.Join(triageFields, ", "))
+			}
+		}
+		h.projectConfig = config
+		return nil
+	}
+}
+
 // validate checks if the Helper is properly initialized
 func (h *Helper) validate() error {
 	if h.githubClient == nil {
@@ -110,22 +393,78 @@ func (h *Helper) validate() error {
 	if h.githubEventPath == "" {
 		return errors.New("github event path is required")
 	}
+	if err := h.accountant.CheckPrices(h.aiModels...); err != nil {
+		return fmt.Errorf("ai budget: %w", err)
+	}
+	if h.hasFeature(FeatureProject) && h.projectConfig.Number == 0 && len(h.projectConfig.Milestones) == 0 {
+		return errors.New("project feature requires a project number or milestone rules")
+	}
 	return nil
 }
 
-// Help processes a GitHub issue event and provides AI-powered assistance
-func (h *Helper) Help(ctx context.Context) {
+// Help processes a GitHub issue event and provides AI-powered assistance.
+// It returns the outcome of every enabled feature and an error matching
+// ErrMalformedEvent when the event cannot be read, ErrAuthentication when GitHub
+// or the AI provider rejected the credentials, or ErrFeaturesFailed when feature
+// failures fail the run under the failure policy.
+func (h *Helper) Help(ctx context.Context) (*RunResult, error) {
 	event, err := h.parseEvent()
 	if err != nil {
-		logger.Log.Fatalf("failed to parse event: %v", err)
+		return nil, fmt.Errorf("%w: %w", ErrMalformedEvent, err)
+	}
+	ctx = logger.WithFields(ctx,
+		logger.F("repo", event.Repository.Owner.Login+"/"+event.Repository.Name),
+		logger.F("issue", event.Issue.Number))
+
+	result := &RunResult{}
+	switch {
+	case event.Action == "opened":
+	case event.Action == "edited" && h.hasFeature(FeatureMissingInfo):
+		// Edits are only relevant to re-check previously missing information
+	default:
+		logger.FromContext(ctx).Info("event is not a new issue, skipping")
+		result.skipAll(h.features, "event is not a new issue")
+		h.reportResult(ctx, result)
+		return result, nil
+	}
+
+	reason, err := h.checkPolicy(ctx, event)
+	if err != nil {
+		if isAuthError(err) {
+			return nil, fmt.Errorf("%w: %w", ErrAuthentication, err)
+		}
+		return nil, err
+	}
+	if reason != "" {
+		result.skipAll(h.features, "policy: "+reason)
+		h.reportResult(ctx, result)
+		return result, nil
+	}
+
+	if h.flagInjection(ctx, event) && h.injectionConfig.Skip {
+		result.skipAll(h.features, "suspected prompt injection, flagged for maintainer review")
+		h.reportResult(ctx, result)
+		return result, nil
 	}
 
-	if event.Action != "opened" {
-		logger.Log.Info("event is not a new issue, skipping")
-		return
+	if err := h.processIssue(ctx, event, result); err != nil {
+		return result, err
 	}
 
-	h.processIssue(ctx, event)
+	if result.Failed(h.failurePolicy) {
+		return result, fmt.Errorf("%w: %s", ErrFeaturesFailed, joinFeatures(result.With(OutcomeFailed)))
+	}
+	return result, nil
+}
+
+// hasFeature reports whether the given feature is enabled
+func (h *Helper) hasFeature(feature Feature) bool {
+	for _, f := range h.features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
 }
 
 // parseEvent reads and parses the GitHub event data
@@ -139,86 +478,156 @@ func (h *Helper) parseEvent() (*GitHubEvent, error) {
 	if err := json.Unmarshal(eventData, &event); err != nil {
 		return nil, fmt.Errorf("failed to parse event data: %w", err)
 	}
+	if event.Issue.Number == 0 || event.Repository.Name == "" || event.Repository.Owner.Login == "" {
+		return nil, errors.New("event is not an issue event")
+	}
 
 	return &event, nil
 }
 
-// processIssue handles the analysis and response for a GitHub issue
-func (h *Helper) processIssue(ctx context.Context, event *GitHubEvent) {
-	// Process each enabled feature
-	for _, feature := range h.features {
-		switch feature {
-		case FeatureComment:
-			h.processComment(ctx, event)
-		case FeatureLabel:
-			h.processLabels(ctx, event)
+// processIssue runs the enabled features concurrently, each with its own time limit,
+// and records their outcomes. An authentication failure cancels the other features.
+func (h *Helper) processIssue(ctx context.Context, event *GitHubEvent, result *RunResult) error {
+	h.loadPromptOverrides(ctx, event)
+	errs := make([]error, len(h.features))
+	g, groupCtx := errgroup.WithContext(ctx)
+	groupCtx = withInputs(groupCtx, &issueInputs{ctx: groupCtx})
+	for i, feature := range h.features {
+		if event.Action == "edited" && feature != FeatureMissingInfo {
+			errs[i] = skip("only missing information is re-checked on edits")
+			continue
 		}
+
+		g.Go(func() error {
+			errs[i] = h.runFeature(groupCtx, event, feature)
+			if errs[i] != nil && isAuthError(errs[i]) {
+				// Every other feature would fail the same way
+				return fmt.Errorf("%w: %w", ErrAuthentication, errs[i])
+			}
+			return nil
+		})
 	}
+	authErr := g.Wait()
 
-	logger.Log.Info("completed issue processing")
+	for i, feature := range h.features {
+		err := errs[i]
+		if authErr != nil && errors.Is(err, context.Canceled) && ctx.Err() == nil {
+			err = skip("cancelled after authentication failure")
+		}
+		result.add(feature, err)
+	}
+
+	endGroup := logger.Group("Run report")
+	h.reportResult(ctx, result)
+	h.reportUsage(ctx)
+	h.reportGitHubUsage(ctx)
+	endGroup()
+	if authErr != nil {
+		return authErr
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("run cancelled: %w", ctx.Err())
+	}
+
+	logger.FromContext(ctx).Info("completed issue processing")
+	return nil
+}
+
+// runFeature runs a single feature within its time limit
+func (h *Helper) runFeature(ctx context.Context, event *GitHubEvent, feature Feature) error {
+	timeout := h.timeoutConfig.timeout(feature)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Every feature records the metadata of its own AI requests
+	ctx, _ = ai.WithMetadata(ctx, string(feature))
+	ctx = logger.WithFields(ctx, logger.F("feature", feature))
+
+	var err error
+	switch feature {
+	case FeatureComment:
+		err = h.processComment(ctx, event)
+	case FeatureLabel:
+		err = h.processLabels(ctx, event)
+	case FeatureDuplicate:
+		err = h.processDuplicates(ctx, event)
+	case FeatureMissingInfo:
+		err = h.processMissingInfo(ctx, event)
+	case FeatureTriage:
+		err = h.processTriage(ctx, event)
+	case FeatureAssignee:
+		err = h.processAssignees(ctx, event)
+	case FeatureProject:
+		err = h.processProject(ctx, event)
+	}
+
+	var skipped skipError
+	if err == nil || errors.As(err, &skipped) {
+		return err
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+	logger.FromContext(ctx).Errorf("%s feature failed: %v", feature, err)
+	return err
 }
 
 // processComment handles AI analysis comment feature
-func (h *Helper) processComment(ctx context.Context, event *GitHubEvent) {
-	files, err := h.githubClient.GetRepositoryContent(ctx, event.Repository.Owner.Login, event.Repository.Name)
+func (h *Helper) processComment(ctx context.Context, event *GitHubEvent) error {
+	files, err := h.repositoryFiles(ctx, event)
 	if err != nil {
-		logger.Log.Errorf("failed to get repository content: %v", err)
-		return
+		return err
 	}
 
 	answer, _, err := h.aiService.AnalyzeCode(ctx, event.Issue.Body, files)
 	if err != nil {
-		logger.Log.Errorf("failed to analyze issue: %v", err)
-		return
+		return fmt.Errorf("failed to analyze issue: %w", err)
 	}
 
-	err = h.githubClient.CreateIssueComment(ctx,
-		event.Repository.Owner.Login,
-		event.Repository.Name,
-		event.Issue.Number,
-		h.formatComment(answer))
+	err = h.createComment(ctx, event, h.formatComment(answer))
 	if err != nil {
-		logger.Log.Errorf("failed to create comment: %v", err)
-		return
+		return fmt.Errorf("failed to create comment: %w", err)
 	}
 
-	logger.Log.Info("successfully added AI analysis comment")
+	logger.FromContext(ctx).Info("successfully added AI analysis comment")
+
+	return nil
 }
 
 // processLabels handles label analysis feature
-func (h *Helper) processLabels(ctx context.Context, event *GitHubEvent) {
+func (h *Helper) processLabels(ctx context.Context, event *GitHubEvent) error {
 	// Get repository labels
-	labelInfo, labels, err := h.githubClient.GetLabelsForAIAnalysis(ctx,
-		event.Repository.Owner.Login,
-		event.Repository.Name)
+	labels, err := h.repositoryLabels(ctx, event)
 	if err != nil {
-		logger.Log.Errorf("failed to get repository labels: %v", err)
-		return
+		return err
 	}
 
-	if len(labels) == 0 {
-		logger.Log.Info("no labels found in repository")
-		return
+	if len(labels.labels) == 0 {
+		return skip("no labels found in repository")
 	}
 
 	// Query AI for label suggestions
-	analysis, err := h.aiService.AnalyzeLabels(ctx, event.Issue.Title, event.Issue.Body, labelInfo)
+	analysis, err := h.aiService.AnalyzeLabels(ctx, event.Issue.Title, event.Issue.Body, labels.prompt)
 	if err != nil {
-		logger.Log.Errorf("failed to analyze labels: %v", err)
-		return
+		return fmt.Errorf("failed to analyze labels: %w", err)
 	}
 
-	// Filter labels with high confidence (> 0.7)
+	// Filter labels with high confidence (> 0.7) that exist in the repository,
+	// the model must not be able to create labels
 	var suggestedLabels []string
 	for label, confidence := range analysis.SuggestedLabels {
-		if confidence >= 0.7 {
-			suggestedLabels = append(suggestedLabels, label)
+		name, ok := labels.name(label)
+		if !ok {
+			logger.FromContext(ctx).Warnf("ignoring suggested label %q, it does not exist in the repository", label)
+			continue
+		}
+		if confidence >= 0.7 && !slices.Contains(suggestedLabels, name) {
+			suggestedLabels = append(suggestedLabels, name)
 		}
 	}
 
 	if len(suggestedLabels) == 0 {
-		logger.Log.Info("no high-confidence label suggestions found")
-		return
+		return skip("no high-confidence label suggestions found")
 	}
 
 	// Add suggested labels to the issue
@@ -227,22 +636,32 @@ func (h *Helper) processLabels(ctx context.Context, event *GitHubEvent) {
 		event.Repository.Name,
 		event.Issue.Number,
 		suggestedLabels); err != nil {
-		logger.Log.Errorf("failed to add labels to issue: %v", err)
-		return
+		return fmt.Errorf("failed to add labels to issue: %w", err)
 	}
 
 	// Add explanation as a comment
 	comment := h.formatLabelExplanation(suggestedLabels, analysis.Explanation)
-	if err := h.githubClient.CreateIssueComment(ctx,
+	if err := h.createComment(ctx, event, comment); err != nil {
+		return fmt.Errorf("failed to add label explanation comment: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("successfully added labels and explanation comment")
+
+	return nil
+}
+
+// createComment posts a comment on the issue, recording the AI metadata of the feature in a hidden footer
+func (h *Helper) createComment(ctx context.Context, event *GitHubEvent, comment string) error {
+	md := ai.MetadataFromContext(ctx)
+	if provider := md.Provider(); provider != "" {
+		comment += fmt.Sprintf("\n\n<!-- issue-assistant provider=%s prompt=%s -->", provider, md.Prompt())
+	}
+
+	return h.githubClient.CreateIssueComment(ctx,
 		event.Repository.Owner.Login,
 		event.Repository.Name,
 		event.Issue.Number,
-		comment); err != nil {
-		logger.Log.Errorf("failed to add label explanation comment: %v", err)
-		return
-	}
-
-	logger.Log.Info("successfully added labels and explanation comment")
+		comment)
 }
 
 // formatLabelExplanation formats the label explanation as a GitHub issue comment
@@ -258,7 +677,7 @@ I've added the following labels to this issue:
 ---
 _This label analysis was performed by [Issue Assistant](https://github.com/workflowkit/issue-assistant). If you have any questions, please contact the repository maintainers._`,
 		formatLabelList(labels),
-		explanation,
+		sanitizeModelText(explanation),
 	)
 }
 
@@ -278,5 +697,5 @@ func (h *Helper) formatComment(answer string) string {
 %s
 
 ---
-_This analysis was performed by [Issue Assistant](https://github.com/workflowkit/issue-assistant). If you have any questions, please contact the repository maintainers._`, answer)
+_This analysis was performed by [Issue Assistant](https://github.com/workflowkit/issue-assistant). If you have any questions, please contact the repository maintainers._`, sanitizeModelText(answer))
 }
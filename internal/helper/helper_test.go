@@ -0,0 +1,359 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/workflowkit/issue-assistant/pkg/ai"
+	"github.com/workflowkit/issue-assistant/pkg/ai/aitest"
+	pkggithub "github.com/workflowkit/issue-assistant/pkg/github"
+	"github.com/workflowkit/issue-assistant/pkg/github/githubtest"
+)
+
+const (
+	testOwner = "acme"
+	testRepo  = "widgets"
+)
+
+const bugTemplate = `---
+name: Bug report
+title: "[Bug]: "
+labels: bug
+---
+## Steps to reproduce
+
+## Expected behavior
+
+## Version
+`
+
+// fixtureRepository is the repository of the issues in testdata/events
+func fixtureRepository() githubtest.Repository {
+	return githubtest.Repository{
+		Owner: testOwner,
+		Name:  testRepo,
+		Files: map[string]string{
+			"README.md":                            "# widgets\n\nConfigurable widgets for dashboards.\n",
+			"config/config.go":                     "package config\n\n// Load reads the configuration file\nfunc Load(path string) (*Config, error) {\n\treturn parse(path)\n}\n",
+			"render/render.go":                     "package render\n\n// Render draws a widget\nfunc Render(w Widget) {}\n",
+			".github/ISSUE_TEMPLATE/bug_report.md": bugTemplate,
+		},
+		Labels: []githubtest.Label{
+			{Name: "bug", Description: "Something isn't working"},
+			{Name: "enhancement", Description: "New feature or request"},
+			{Name: "documentation", Description: "Improvements or additions to documentation"},
+			{Name: "duplicate", Description: "This issue already exists"},
+			{Name: "needs-info", Description: "More information is needed"},
+		},
+		Issues: []githubtest.Issue{
+			{
+				Number: 7,
+				Title:  "Panic when the config file is empty",
+				Body:   "widgets panics with a nil pointer dereference when the config file is empty.",
+				Author: "monalisa",
+			},
+			{
+				Number:            12,
+				Title:             "[Bug]: Crash when the config file is empty",
+				Body:              "## Steps to reproduce\nRun `widgets --config empty.yaml` with an empty file.\n\n## Expected behavior\nA clear error message instead of a panic.\n\n## Version\n",
+				Author:            "octocat",
+				AuthorAssociation: "CONTRIBUTOR",
+				CreatedAt:         time.Now(),
+			},
+			{
+				Number:            13,
+				Title:             "[Bug]: Widgets render twice on resize",
+				Body:              "## Steps to reproduce\nResize the window while a widget is open.\n\n## Expected behavior\nThe widget renders once.\n\n## Version\nv1.4.2\n",
+				Author:            "hubot",
+				AuthorAssociation: "NONE",
+				Labels:            []string{"needs-info"},
+			},
+		},
+	}
+}
+
+// newTestServer starts a fake GitHub server serving the fixture repository
+func newTestServer(t *testing.T) *githubtest.Server {
+	t.Helper()
+	srv := githubtest.NewServer(fixtureRepository())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// fixtureEvent returns the path of the fixture event testdata/events/<name>.json
+func fixtureEvent(name string) string {
+	return filepath.Join("testdata", "events", name+".json")
+}
+
+// newTestHelper creates a helper for the event at eventPath talking to the fake server and the scripted AI
+func newTestHelper(t *testing.T, srv *githubtest.Server, mock *aitest.Mock, eventPath string, opts ...Option) *Helper {
+	t.Helper()
+	// Keep outputs and the job summary out of the workflow running the tests
+	t.Setenv("GITHUB_OUTPUT", "")
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewHelper(append([]Option{
+		WithGitHubService(client),
+		WithAIProvider("mock", mock),
+		WithGitHubEventPath(eventPath),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// outcomes maps the features of a run result to their outcomes
+func outcomes(result *RunResult) map[Feature]Outcome {
+	outcomes := make(map[Feature]Outcome)
+	for _, feature := range result.Features {
+		outcomes[feature.Feature] = feature.Outcome
+	}
+	return outcomes
+}
+
+// reason returns the reason recorded for a feature
+func reason(result *RunResult, feature Feature) string {
+	for _, r := range result.Features {
+		if r.Feature == feature {
+			return r.Reason
+		}
+	}
+	return ""
+}
+
+func TestHelpRunsFeaturesWithScriptedAI(t *testing.T) {
+	mock := &aitest.Mock{
+		Code: []aitest.Response[aitest.CodeAnswer]{{Value: aitest.CodeAnswer{Answer: "config.Load does not handle empty files.", Confidence: 0.8}}},
+		Labels: []aitest.Response[pkggithub.LabelAnalysis]{{Value: pkggithub.LabelAnalysis{
+			SuggestedLabels: map[string]float64{"bug": 0.9},
+			Explanation:     "The issue reports a crash.",
+		}}},
+	}
+	h := newTestHelper(t, newTestServer(t), mock, fixtureEvent("opened"), WithFeatures([]Feature{FeatureComment, FeatureLabel}))
+
+	result, err := h.Help(context.Background())
+	if err != nil {
+		t.Fatalf("Help failed: %v", err)
+	}
+
+	want := map[Feature]Outcome{FeatureComment: OutcomeSucceeded, FeatureLabel: OutcomeSucceeded}
+	if got := outcomes(result); !maps.Equal(got, want) {
+		t.Errorf("outcomes = %v, want %v", got, want)
+	}
+
+	labelCalls := mock.CallsTo("AnalyzeLabels")
+	if len(labelCalls) != 1 || labelCalls[0].Title != "[Bug]: Crash when the config file is empty" {
+		t.Errorf("AnalyzeLabels calls = %+v, want one with the title of the event", labelCalls)
+	}
+	codeCalls := mock.CallsTo("AnalyzeCode")
+	if len(codeCalls) != 1 || !strings.Contains(codeCalls[0].Title, "widgets --config empty.yaml") {
+		t.Errorf("AnalyzeCode calls = %+v, want one with the body of the event", codeCalls)
+	}
+}
+
+func TestHelpSkipsOtherActions(t *testing.T) {
+	mock := &aitest.Mock{}
+	h := newTestHelper(t, newTestServer(t), mock, fixtureEvent("closed"), WithFeatures([]Feature{FeatureComment, FeatureLabel}))
+
+	result, err := h.Help(context.Background())
+	if err != nil {
+		t.Fatalf("Help failed: %v", err)
+	}
+	if got := result.With(OutcomeSkipped); len(got) != 2 {
+		t.Errorf("skipped features = %v, want all", got)
+	}
+	if got := reason(result, FeatureLabel); got != "event is not a new issue" {
+		t.Errorf("reason = %q", got)
+	}
+	if calls := mock.Calls(); len(calls) != 0 {
+		t.Errorf("AI was called: %+v", calls)
+	}
+}
+
+func TestHelpEditedRechecksMissingInfoOnly(t *testing.T) {
+	mock := &aitest.Mock{}
+	h := newTestHelper(t, newTestServer(t), mock, fixtureEvent("edited"), WithFeatures([]Feature{FeatureLabel, FeatureMissingInfo}))
+
+	result, err := h.Help(context.Background())
+	if err != nil {
+		t.Fatalf("Help failed: %v", err)
+	}
+	if got := outcomes(result); got[FeatureLabel] != OutcomeSkipped || got[FeatureMissingInfo] != OutcomeSucceeded {
+		t.Errorf("outcomes = %v, want label skipped and missing information succeeded", got)
+	}
+	if got := reason(result, FeatureLabel); got != "only missing information is re-checked on edits" {
+		t.Errorf("label reason = %q", got)
+	}
+	if calls := mock.Calls(); len(calls) != 0 {
+		t.Errorf("AI was called: %+v", calls)
+	}
+}
+
+func TestHelpMalformedEvent(t *testing.T) {
+	h := newTestHelper(t, newTestServer(t), &aitest.Mock{}, fixtureEvent("push"), WithFeatures([]Feature{FeatureComment}))
+
+	if _, err := h.Help(context.Background()); !errors.Is(err, ErrMalformedEvent) {
+		t.Errorf("got %v, want ErrMalformedEvent", err)
+	}
+}
+
+func TestHelpFailurePolicy(t *testing.T) {
+	tests := []struct {
+		policy  FailurePolicy
+		wantErr bool
+	}{
+		{FailOnAny, true},
+		{FailOnAll, false},
+		{FailOnNever, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			mock := &aitest.Mock{
+				Code:   []aitest.Response[aitest.CodeAnswer]{{Value: aitest.CodeAnswer{Answer: "Check config.Load.", Confidence: 0.8}}},
+				Labels: []aitest.Response[pkggithub.LabelAnalysis]{{Err: errors.New("model overloaded")}},
+			}
+			h := newTestHelper(t, newTestServer(t), mock, fixtureEvent("opened"),
+				WithFeatures([]Feature{FeatureComment, FeatureLabel}),
+				WithFailurePolicy(tt.policy))
+
+			result, err := h.Help(context.Background())
+			if got := errors.Is(err, ErrFeaturesFailed); got != tt.wantErr {
+				t.Errorf("Help error = %v, want ErrFeaturesFailed %v", err, tt.wantErr)
+			}
+			if got := outcomes(result); got[FeatureComment] != OutcomeSucceeded || got[FeatureLabel] != OutcomeFailed {
+				t.Errorf("outcomes = %v, want comment succeeded and label failed", got)
+			}
+			if got := reason(result, FeatureLabel); !strings.Contains(got, "model overloaded") {
+				t.Errorf("label reason = %q, want the AI error", got)
+			}
+		})
+	}
+}
+
+func TestHelpAuthenticationFailure(t *testing.T) {
+	rejected := &ai.RequestError{Provider: "mock", StatusCode: 401, Attempts: 1, Err: errors.New("invalid x-api-key")}
+	mock := &aitest.Mock{Labels: []aitest.Response[pkggithub.LabelAnalysis]{{Err: rejected}}}
+	h := newTestHelper(t, newTestServer(t), mock, fixtureEvent("opened"), WithFeatures([]Feature{FeatureLabel}))
+
+	if _, err := h.Help(context.Background()); !errors.Is(err, ErrAuthentication) {
+		t.Errorf("got %v, want ErrAuthentication", err)
+	}
+}
+
+func TestHelpGitHubPermissionFailure(t *testing.T) {
+	srv := newTestServer(t)
+	// The token may write comments but not labels
+	srv.Reject(githubtest.MutationAddLabels, http.StatusForbidden, "Resource not accessible by integration")
+	mock := &aitest.Mock{
+		Code: []aitest.Response[aitest.CodeAnswer]{{Value: aitest.CodeAnswer{Answer: "Check config.Load.", Confidence: 0.8}}},
+		Labels: []aitest.Response[pkggithub.LabelAnalysis]{{Value: pkggithub.LabelAnalysis{
+			SuggestedLabels: map[string]float64{"bug": 0.9},
+			Explanation:     "The issue reports a crash.",
+		}}},
+	}
+	h := newTestHelper(t, srv, mock, fixtureEvent("opened"), WithFeatures([]Feature{FeatureComment, FeatureLabel}))
+
+	result, err := h.Help(context.Background())
+	if !errors.Is(err, ErrFeaturesFailed) || errors.Is(err, ErrAuthentication) {
+		t.Errorf("got %v, want ErrFeaturesFailed only", err)
+	}
+	if got := outcomes(result); got[FeatureComment] != OutcomeSucceeded || got[FeatureLabel] != OutcomeFailed {
+		t.Errorf("outcomes = %v, want comment succeeded and label failed", got)
+	}
+	if comments := srv.MutationsOf(githubtest.MutationComment); len(comments) != 1 {
+		t.Errorf("comments = %+v, want the analysis comment", comments)
+	}
+}
+
+func TestHelpGitHubAuthenticationFailure(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Reject(githubtest.MutationAddLabels, http.StatusUnauthorized, "Bad credentials")
+	mock := &aitest.Mock{Labels: []aitest.Response[pkggithub.LabelAnalysis]{{Value: pkggithub.LabelAnalysis{
+		SuggestedLabels: map[string]float64{"bug": 0.9},
+		Explanation:     "The issue reports a crash.",
+	}}}}
+	h := newTestHelper(t, srv, mock, fixtureEvent("opened"), WithFeatures([]Feature{FeatureLabel}))
+
+	if _, err := h.Help(context.Background()); !errors.Is(err, ErrAuthentication) {
+		t.Errorf("got %v, want ErrAuthentication", err)
+	}
+}
+
+func TestHelpConcurrentRuns(t *testing.T) {
+	srv := newTestServer(t)
+	mock := &aitest.Mock{Triage: []aitest.Response[pkggithub.TriageAnalysis]{
+		{Value: pkggithub.TriageAnalysis{Type: "bug", Severity: "high", Priority: "p1", Summary: "A crash."}},
+		{Value: pkggithub.TriageAnalysis{Type: "feature", Severity: "low", Priority: "p3", Summary: "A request."}},
+	}}
+	h := newTestHelper(t, srv, mock, fixtureEvent("opened"),
+		WithFeatures([]Feature{FeatureTriage}),
+		WithTriageConfig(TriageConfig{
+			Labels:         map[string]string{"type:bug": "bug", "type:feature": "enhancement"},
+			ComponentDepth: 2,
+		}))
+
+	// Runs of the same helper must not share the triage result of an issue
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := h.Help(context.Background())
+			errs <- err
+		}()
+	}
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Errorf("Help failed: %v", err)
+		}
+	}
+
+	if calls := mock.CallsTo("AnalyzeTriage"); len(calls) != 2 {
+		t.Errorf("AnalyzeTriage calls = %+v, want one per run", calls)
+	}
+	var labels []string
+	for _, mutation := range srv.MutationsOf(githubtest.MutationAddLabels) {
+		labels = append(labels, mutation.Values...)
+	}
+	slices.Sort(labels)
+	if !slices.Equal(labels, []string{"bug", "enhancement"}) {
+		t.Errorf("added labels = %v, want the labels of both runs", labels)
+	}
+}
+
+func TestNewHelperBudgetRequiresPrices(t *testing.T) {
+	srv := newTestServer(t)
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newHelper := func(cost CostConfig) error {
+		_, err := NewHelper(
+			WithGitHubService(client),
+			WithAIService("ollama", "", ai.WithModelConfig(ai.ModelConfig{Model: "llama3.1"})),
+			WithGitHubEventPath(fixtureEvent("opened")),
+			WithCostConfig(cost))
+		return err
+	}
+
+	if err := newHelper(CostConfig{Budget: 0.05}); err == nil || !strings.Contains(err.Error(), "llama3.1") {
+		t.Errorf("got %v, want an error naming the unpriced model", err)
+	}
+	if err := newHelper(CostConfig{Budget: 0.05, DefaultPrice: &ai.Price{}}); err != nil {
+		t.Errorf("with a default price: %v", err)
+	}
+	if err := newHelper(CostConfig{}); err != nil {
+		t.Errorf("without a budget: %v", err)
+	}
+}
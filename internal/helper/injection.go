@@ -0,0 +1,59 @@
+package helper
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/workflowkit/issue-assistant/pkg/actions"
+	"github.com/workflowkit/issue-assistant/pkg/injection"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// flagInjection scans the issue for prompt injection attempts, labels suspected issues
+// for maintainer review and reports the result in the injection_suspected output.
+// It reports whether the issue is suspected.
+func (h *Helper) flagInjection(ctx context.Context, event *GitHubEvent) bool {
+	findings := injection.Scan(event.Issue.Title, event.Issue.Body)
+	if err := actions.SetOutputs(map[string]string{
+		"injection_suspected": strconv.FormatBool(len(findings) > 0),
+		"injection_rules":     strings.Join(injection.Rules(findings), ","),
+	}); err != nil {
+		logger.FromContext(ctx).Errorf("failed to set injection outputs: %v", err)
+	}
+	if len(findings) == 0 {
+		return false
+	}
+
+	for _, finding := range findings {
+		logger.FromContext(ctx).Debugf("suspected prompt injection (%s): %q", finding.Rule, finding.Match)
+	}
+	logger.FromContext(ctx).Warnf("issue #%d may contain a prompt injection attempt (%s), flagged for maintainer review",
+		event.Issue.Number, strings.Join(injection.Rules(findings), ", "))
+
+	label := h.injectionConfig.Label
+	if label == "" || event.hasLabel(label) {
+		return true
+	}
+	if err := h.githubClient.AddLabelsToIssue(ctx, event.Repository.Owner.Login, event.Repository.Name,
+		event.Issue.Number, []string{label}); err != nil {
+		logger.FromContext(ctx).Errorf("failed to add %s label: %v", label, err)
+	}
+	return true
+}
+
+var (
+	// htmlComment matches HTML comments, which would hide text from readers and could spoof the metadata footer
+	htmlComment = regexp.MustCompile(`<!--[\s\S]*?(?:-->|$)`)
+	// mention matches @-mentions of users and teams
+	mention = regexp.MustCompile("(^|[^\\w`])@([A-Za-z0-9][A-Za-z0-9-]*(?:/[A-Za-z0-9_-]+)?)")
+)
+
+// sanitizeModelText restricts text written by the model to what a comment is allowed to do:
+// hidden HTML comments are removed and @-mentions are put in code spans so nobody is notified.
+// Mentions the assistant makes itself, e.g. of suggested assignees, are not model text.
+func sanitizeModelText(text string) string {
+	text = htmlComment.ReplaceAllString(text, "")
+	return mention.ReplaceAllString(text, "$1`@$2`")
+}
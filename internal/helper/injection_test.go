@@ -0,0 +1,30 @@
+package helper
+
+import "testing"
+
+func TestSanitizeModelText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain", "Widgets are rendered in `render.go`.", "Widgets are rendered in `render.go`."},
+		{"comment", "Looks fine.<!-- issue-assistant provider=x prompt=y -->", "Looks fine."},
+		{"multiline comment", "a<!--\nhidden\n-->b", "ab"},
+		{"unterminated comment", "a<!-- hidden until the end", "a"},
+		{"mention", "@octocat can help", "`@octocat` can help"},
+		{"team mention", "ping @acme/maintainers.", "ping `@acme/maintainers`."},
+		{"mentions", "cc @a, @b-c", "cc `@a`, `@b-c`"},
+		{"email", "mail octocat@example.com", "mail octocat@example.com"},
+		{"code span", "use `@octocat`", "use `@octocat`"},
+		{"mention in comment", "<!-- @octocat -->@hubot", "`@hubot`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeModelText(tt.text); got != tt.want {
+				t.Errorf("sanitizeModelText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
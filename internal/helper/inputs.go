@@ -0,0 +1,118 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	gogithub "github.com/google/go-github/v45/github"
+	pkggithub "github.com/workflowkit/issue-assistant/pkg/github"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// lazy is a value loaded on first use and shared by concurrent callers.
+// The value is loaded once, with the context of the run rather than the context of
+// the first caller, so a feature with a short time limit cannot fail the load for
+// the others. Callers stop waiting when their own context is done.
+type lazy[T any] struct {
+	once  sync.Once
+	done  chan struct{}
+	value T
+	err   error
+}
+
+func (l *lazy[T]) get(ctx, runCtx context.Context, load func(ctx context.Context) (T, error)) (T, error) {
+	l.once.Do(func() {
+		l.done = make(chan struct{})
+		go func() {
+			defer close(l.done)
+			l.value, l.err = load(runCtx)
+		}()
+	})
+
+	select {
+	case <-l.done:
+		return l.value, l.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// repositoryLabels are the labels of the repository with their prompt description
+type repositoryLabels struct {
+	prompt string
+	labels []*gogithub.Label
+}
+
+// name returns the name of the repository label matching name case-insensitively
+func (l repositoryLabels) name(name string) (string, bool) {
+	for _, label := range l.labels {
+		if strings.EqualFold(label.GetName(), strings.TrimSpace(name)) {
+			return label.GetName(), true
+		}
+	}
+	return "", false
+}
+
+// issueInputs are the analyses shared by the features processing an issue, made once
+// when the first feature needs them, with the context of the run. Repository reads are
+// shared through the caching GitHub service instead.
+type issueInputs struct {
+	// ctx is the context of the run, canceled when the run is canceled
+	ctx    context.Context
+	triage lazy[pkggithub.TriageAnalysis]
+}
+
+type inputsKey struct{}
+
+// withInputs returns a context carrying the shared inputs of a run
+func withInputs(ctx context.Context, inputs *issueInputs) context.Context {
+	return context.WithValue(ctx, inputsKey{}, inputs)
+}
+
+// inputsFromContext returns the shared inputs of the run, or inputs of their own
+// for callers outside of a run
+func inputsFromContext(ctx context.Context) *issueInputs {
+	if inputs, ok := ctx.Value(inputsKey{}).(*issueInputs); ok {
+		return inputs
+	}
+	return &issueInputs{ctx: ctx}
+}
+
+// repositoryFiles returns the relevant files of the repository, without files that likely hold credentials
+func (h *Helper) repositoryFiles(ctx context.Context, event *GitHubEvent) ([]pkggithub.GitHubFile, error) {
+	files, err := h.githubClient.GetRepositoryContent(ctx, event.Repository.Owner.Login, event.Repository.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository content: %w", err)
+	}
+
+	relevant := make([]pkggithub.GitHubFile, 0, len(files))
+	for _, file := range files {
+		if h.redactor.IsSecretFile(file.Path) {
+			logger.FromContext(ctx).Debugf("excluding secret file %s from context", file.Path)
+			continue
+		}
+		relevant = append(relevant, file)
+	}
+	return relevant, nil
+}
+
+// repositoryLabels returns the labels of the repository
+func (h *Helper) repositoryLabels(ctx context.Context, event *GitHubEvent) (repositoryLabels, error) {
+	prompt, labels, err := h.githubClient.GetLabelsForAIAnalysis(ctx, event.Repository.Owner.Login, event.Repository.Name)
+	if err != nil {
+		return repositoryLabels{}, fmt.Errorf("failed to get repository labels: %w", err)
+	}
+	return repositoryLabels{prompt: prompt, labels: labels}, nil
+}
+
+// repositoryTree returns the paths of all files on the default branch
+func (h *Helper) repositoryTree(ctx context.Context, event *GitHubEvent) ([]string, error) {
+	paths, err := h.githubClient.GetRepositoryTree(ctx, event.Repository.Owner.Login, event.Repository.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository tree: %w", err)
+	}
+	return paths, nil
+}
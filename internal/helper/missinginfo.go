@@ -0,0 +1,113 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/workflowkit/issue-assistant/pkg/issuetemplate"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// issueTemplateDir is where GitHub looks for issue templates
+const issueTemplateDir = ".github/ISSUE_TEMPLATE"
+
+// processMissingInfo handles missing template information feature
+func (h *Helper) processMissingInfo(ctx context.Context, event *GitHubEvent) error {
+	owner := event.Repository.Owner.Login
+	repo := event.Repository.Name
+
+	labeled := h.missingInfoConfig.Label != "" && event.hasLabel(h.missingInfoConfig.Label)
+	if event.Action == "edited" && !labeled {
+		return skip("issue is not waiting for information")
+	}
+
+	files, err := h.githubClient.GetDirectoryFiles(ctx, owner, repo, issueTemplateDir)
+	if err != nil {
+		return fmt.Errorf("failed to get issue templates: %w", err)
+	}
+
+	var templates []issuetemplate.Template
+	for _, file := range files {
+		template, ok, err := issuetemplate.Parse(file.Path, file.Content)
+		if err != nil {
+			logger.FromContext(ctx).Warnf("skipping issue template: %v", err)
+			continue
+		}
+		if ok {
+			templates = append(templates, template)
+		}
+	}
+
+	if len(templates) == 0 {
+		return skip("no issue templates found in repository")
+	}
+
+	template, ok := issuetemplate.Match(templates, event.Issue.Title, event.labelNames(), event.Issue.Body)
+	if !ok {
+		return skip("issue does not match any issue template")
+	}
+
+	missing := template.Missing(event.Issue.Body)
+	logger.FromContext(ctx).Infof("issue matches template %s with %d missing sections", template.Path, len(missing))
+
+	if len(missing) == 0 {
+		if labeled {
+			if err := h.githubClient.RemoveLabelFromIssue(ctx, owner, repo, event.Issue.Number, h.missingInfoConfig.Label); err != nil {
+				return fmt.Errorf("failed to remove %s label: %w", h.missingInfoConfig.Label, err)
+			}
+			logger.FromContext(ctx).Infof("all required information supplied, removed %s label", h.missingInfoConfig.Label)
+		}
+		return nil
+	}
+
+	if labeled {
+		return skip("information is still missing, already requested")
+	}
+
+	if err := h.createComment(ctx, event, h.formatMissingInfoComment(template, missing)); err != nil {
+		return fmt.Errorf("failed to create missing information comment: %w", err)
+	}
+
+	if h.missingInfoConfig.Label != "" {
+		if err := h.githubClient.AddLabelsToIssue(ctx, owner, repo, event.Issue.Number, []string{h.missingInfoConfig.Label}); err != nil {
+			return fmt.Errorf("failed to add %s label: %w", h.missingInfoConfig.Label, err)
+		}
+	}
+
+	logger.FromContext(ctx).Info("successfully requested missing information")
+
+	return nil
+}
+
+// formatMissingInfoComment formats the request for missing sections as a GitHub issue comment
+func (h *Helper) formatMissingInfoComment(template issuetemplate.Template, missing []issuetemplate.Section) string {
+	var sections strings.Builder
+	for _, section := range missing {
+		sections.WriteString(fmt.Sprintf("- **%s**\n", section.Heading))
+	}
+
+	name := template.Name
+	if name == "" {
+		name = template.Path
+	}
+
+	var followUp string
+	if h.missingInfoConfig.Label != "" {
+		followUp = fmt.Sprintf(" The `%s` label will be removed once the information is supplied.", h.missingInfoConfig.Label)
+	}
+
+	return fmt.Sprintf(`📝 AI Issue Assistant
+
+Thanks for opening this issue! Some information requested by the **%s** template is missing or still contains the template placeholder:
+
+%s
+Please edit the issue description and fill in the sections above so maintainers can look into it.%s
+
+---
+_This check was performed by [Issue Assistant](https://github.com/workflowkit/issue-assistant). If you have any questions, please contact the repository maintainers._`,
+		name,
+		sections.String(),
+		followUp,
+	)
+}
@@ -0,0 +1,151 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// policyCheck is a rule of the policy. It returns why the issue is skipped, or an
+// empty reason when the issue passes.
+type policyCheck struct {
+	name  string
+	check func(ctx context.Context, event *GitHubEvent) (string, error)
+}
+
+// checkPolicy evaluates the policy rules in order, cheap checks of the event before
+// the ones calling the GitHub API, and returns why the issue is skipped or an empty
+// reason when it is processed. Every decision is explained in the log. A rule that
+// cannot be checked lets the issue pass, or skips it with SkipOnError; only
+// cancellation and rejected credentials fail the run.
+func (h *Helper) checkPolicy(ctx context.Context, event *GitHubEvent) (string, error) {
+	checks := []policyCheck{
+		{"bots", h.checkBot},
+		{"deny-list", h.checkDenyList},
+		{"author association", h.checkAuthorAssociation},
+		{"labels", h.checkLabels},
+		{"forks", h.checkFork},
+		{"account age", h.checkAccountAge},
+		{"issues opened per day", h.checkDailyLimit},
+	}
+
+	for _, c := range checks {
+		reason, err := c.check(ctx, event)
+		if err != nil {
+			if ctx.Err() != nil || isAuthError(err) {
+				return "", fmt.Errorf("failed to check %s policy: %w", c.name, err)
+			}
+			if h.policyConfig.SkipOnError {
+				reason = fmt.Sprintf("failed to check %s policy: %v", c.name, err)
+			} else {
+				logger.FromContext(ctx).Warnf("failed to check %s policy, letting the issue pass: %v", c.name, err)
+				continue
+			}
+		}
+		if reason != "" {
+			logger.FromContext(ctx).Infof("policy skips issue: %s", reason)
+			return reason, nil
+		}
+		logger.FromContext(ctx).Debugf("policy check %s passed", c.name)
+	}
+
+	logger.FromContext(ctx).Infof("policy allows issue by %s (%s)", event.Issue.User.Login, event.Issue.AuthorAssociation)
+	return "", nil
+}
+
+func (h *Helper) checkBot(_ context.Context, event *GitHubEvent) (string, error) {
+	login := event.Issue.User.Login
+	if h.policyConfig.AllowBots || (event.Issue.User.Type != "Bot" && !strings.HasSuffix(login, "[bot]")) {
+		return "", nil
+	}
+	return fmt.Sprintf("author %s is a bot", login), nil
+}
+
+func (h *Helper) checkDenyList(_ context.Context, event *GitHubEvent) (string, error) {
+	login := event.Issue.User.Login
+	if !slices.ContainsFunc(h.policyConfig.DenyAuthors, func(denied string) bool { return strings.EqualFold(denied, login) }) {
+		return "", nil
+	}
+	return fmt.Sprintf("author %s is deny-listed", login), nil
+}
+
+func (h *Helper) checkAuthorAssociation(_ context.Context, event *GitHubEvent) (string, error) {
+	allowed := h.policyConfig.AuthorAssociations
+	association := event.Issue.AuthorAssociation
+	if len(allowed) == 0 || slices.ContainsFunc(allowed, func(a string) bool { return strings.EqualFold(a, association) }) {
+		return "", nil
+	}
+	return fmt.Sprintf("author association %s is not one of %s", association, strings.Join(allowed, ", ")), nil
+}
+
+func (h *Helper) checkLabels(_ context.Context, event *GitHubEvent) (string, error) {
+	for _, label := range h.policyConfig.SkipLabels {
+		if event.hasLabel(label) {
+			return fmt.Sprintf("issue has skip label %s", label), nil
+		}
+	}
+
+	required := h.policyConfig.RequireLabels
+	if len(required) == 0 || slices.ContainsFunc(required, event.hasLabel) {
+		return "", nil
+	}
+	return fmt.Sprintf("issue has none of the required labels %s", strings.Join(required, ", ")), nil
+}
+
+func (h *Helper) checkFork(_ context.Context, event *GitHubEvent) (string, error) {
+	if h.policyConfig.AllowForks || !event.Repository.Fork {
+		return "", nil
+	}
+	return fmt.Sprintf("repository %s/%s is a fork", event.Repository.Owner.Login, event.Repository.Name), nil
+}
+
+// checkAccountAge skips authors with new accounts, which are typical of spam
+func (h *Helper) checkAccountAge(ctx context.Context, event *GitHubEvent) (string, error) {
+	minDays := h.policyConfig.MinAccountAgeDays
+	if minDays <= 0 {
+		return "", nil
+	}
+
+	user, err := h.githubClient.GetUser(ctx, event.Issue.User.Login)
+	if err != nil {
+		return "", err
+	}
+	age := time.Since(user.CreatedAt)
+	if age >= time.Duration(minDays)*24*time.Hour {
+		return "", nil
+	}
+	return fmt.Sprintf("account of %s is %d days old, younger than %d days", user.Login, int(age.Hours()/24), minDays), nil
+}
+
+// checkDailyLimit counts the issues the author opened in the repository within the last 24 hours.
+// It limits opened issues, not runs: edits of an issue that passed when it was opened are not checked.
+func (h *Helper) checkDailyLimit(ctx context.Context, event *GitHubEvent) (string, error) {
+	limit := h.policyConfig.MaxIssuesOpenedPerDay
+	if limit <= 0 || event.Action != "opened" {
+		return "", nil
+	}
+
+	login := event.Issue.User.Login
+	since := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	issues, err := h.githubClient.SearchIssues(ctx, event.Repository.Owner.Login, event.Repository.Name,
+		fmt.Sprintf("author:%s created:>=%s", login, since), limit+1)
+	if err != nil {
+		return "", err
+	}
+
+	// The search index may not contain the current issue yet
+	var earlier int
+	for _, issue := range issues {
+		if issue.Number != event.Issue.Number {
+			earlier++
+		}
+	}
+	if earlier < limit {
+		return "", nil
+	}
+	return fmt.Sprintf("author %s opened %d other issues within 24 hours, the limit is %d", login, earlier, limit), nil
+}
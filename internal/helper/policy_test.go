@@ -0,0 +1,133 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/workflowkit/issue-assistant/pkg/ai/aitest"
+	"github.com/workflowkit/issue-assistant/pkg/github/githubtest"
+)
+
+func TestPolicy(t *testing.T) {
+	now := time.Now()
+	established := githubtest.User{Login: "octocat", CreatedAt: now.AddDate(-3, 0, 0)}
+
+	tests := []struct {
+		name string
+		// action is the event action, opened by default
+		action string
+		config PolicyConfig
+		users  []githubtest.User
+		issues []githubtest.Issue
+		// wantReason is the reason the issue is skipped, empty when it is processed
+		wantReason string
+	}{
+		{
+			name:   "allowed",
+			config: PolicyConfig{AuthorAssociations: []string{"MEMBER", "CONTRIBUTOR"}, MinAccountAgeDays: 30, MaxIssuesOpenedPerDay: 2},
+			users:  []githubtest.User{established},
+		},
+		{
+			name:       "bot",
+			users:      []githubtest.User{{Login: "octocat", Type: "Bot", CreatedAt: now.AddDate(-3, 0, 0)}},
+			wantReason: "policy: author octocat is a bot",
+		},
+		{
+			name:       "deny-listed",
+			config:     PolicyConfig{DenyAuthors: []string{"OctoCat"}},
+			wantReason: "policy: author octocat is deny-listed",
+		},
+		{
+			name:       "author association",
+			config:     PolicyConfig{AuthorAssociations: []string{"OWNER", "MEMBER"}},
+			wantReason: "policy: author association CONTRIBUTOR is not one of OWNER, MEMBER",
+		},
+		{
+			name:       "required label",
+			config:     PolicyConfig{RequireLabels: []string{"triage"}},
+			wantReason: "policy: issue has none of the required labels triage",
+		},
+		{
+			name:       "new account",
+			config:     PolicyConfig{MinAccountAgeDays: 30},
+			users:      []githubtest.User{{Login: "octocat", CreatedAt: now.AddDate(0, 0, -2)}},
+			wantReason: "policy: account of octocat is 2 days old, younger than 30 days",
+		},
+		{
+			name:   "daily limit",
+			config: PolicyConfig{MaxIssuesOpenedPerDay: 2},
+			issues: []githubtest.Issue{
+				{Number: 20, Title: "Widgets flicker", Author: "octocat", CreatedAt: now.Add(-time.Hour)},
+				{Number: 21, Title: "Widgets vanish", Author: "octocat", CreatedAt: now.Add(-2 * time.Hour)},
+				{Number: 22, Title: "Old report", Author: "octocat", CreatedAt: now.AddDate(0, 0, -2)},
+			},
+			wantReason: "policy: author octocat opened 2 other issues within 24 hours, the limit is 2",
+		},
+		{
+			// The limit counts opened issues, an edit is left to the features
+			name:   "daily limit on edit",
+			action: "edited",
+			config: PolicyConfig{MaxIssuesOpenedPerDay: 2},
+			issues: []githubtest.Issue{
+				{Number: 20, Title: "Widgets flicker", Author: "octocat", CreatedAt: now.Add(-time.Hour)},
+				{Number: 21, Title: "Widgets vanish", Author: "octocat", CreatedAt: now.Add(-2 * time.Hour)},
+			},
+			wantReason: "issue is not waiting for information",
+		},
+		{
+			name:   "below daily limit",
+			config: PolicyConfig{MaxIssuesOpenedPerDay: 2},
+			issues: []githubtest.Issue{
+				{Number: 20, Title: "Widgets flicker", Author: "octocat", CreatedAt: now.Add(-time.Hour)},
+				{Number: 22, Title: "Old report", Author: "octocat", CreatedAt: now.AddDate(0, 0, -2)},
+			},
+		},
+		{
+			// The account lookup fails with 404, e.g. for a deleted account
+			name:   "failed lookup passes",
+			config: PolicyConfig{MinAccountAgeDays: 30},
+		},
+		{
+			name:       "failed lookup skips",
+			config:     PolicyConfig{MinAccountAgeDays: 30, SkipOnError: true},
+			wantReason: "policy: failed to check account age policy: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := fixtureRepository()
+			repo.Issues = append(repo.Issues, tt.issues...)
+			srv := githubtest.NewServer(repo)
+			t.Cleanup(srv.Close)
+			for _, user := range tt.users {
+				srv.AddUser(user)
+			}
+
+			action := tt.action
+			if action == "" {
+				action = "opened"
+			}
+			h := newTestHelper(t, srv, &aitest.Mock{}, writeEvent(t, srv, action, 12),
+				WithFeatures([]Feature{FeatureMissingInfo}),
+				WithPolicyConfig(tt.config))
+
+			result := help(t, h)
+
+			got := result.Features[0]
+			if tt.wantReason == "" {
+				if got.Outcome != OutcomeSucceeded {
+					t.Errorf("missing information %s (%s), want the issue processed", got.Outcome, got.Reason)
+				}
+				return
+			}
+			if got.Outcome != OutcomeSkipped || !strings.HasPrefix(got.Reason, tt.wantReason) {
+				t.Errorf("missing information %s (%s), want skipped with %q", got.Outcome, got.Reason, tt.wantReason)
+			}
+			if mutations := srv.Mutations(); len(mutations) != 0 {
+				t.Errorf("skipped issue was changed: %+v", mutations)
+			}
+		})
+	}
+}
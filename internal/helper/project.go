@@ -0,0 +1,177 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pkggithub "github.com/workflowkit/issue-assistant/pkg/github"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// Iteration selectors usable as project field sources
+const (
+	currentIteration = "@current"
+	nextIteration    = "@next"
+)
+
+// processProject handles project and milestone placement feature
+func (h *Helper) processProject(ctx context.Context, event *GitHubEvent) error {
+	analysis, err := h.getTriage(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to triage issue: %w", err)
+	}
+
+	if h.projectConfig.Number > 0 {
+		if err := h.placeOnProject(ctx, event, analysis); err != nil {
+			return fmt.Errorf("failed to place issue on project: %w", err)
+		}
+	}
+
+	if len(h.projectConfig.Milestones) > 0 {
+		if err := h.assignMilestone(ctx, event, analysis); err != nil {
+			return fmt.Errorf("failed to assign milestone: %w", err)
+		}
+	}
+
+	logger.FromContext(ctx).Info("successfully completed project placement")
+
+	return nil
+}
+
+// placeOnProject adds the issue to the configured project and sets its fields from the triage result
+func (h *Helper) placeOnProject(ctx context.Context, event *GitHubEvent, analysis pkggithub.TriageAnalysis) error {
+	owner := h.projectConfig.Owner
+	if owner == "" {
+		owner = event.Repository.Owner.Login
+	}
+
+	project, err := h.githubClient.GetProject(ctx, owner, h.projectConfig.Number)
+	if err != nil {
+		return err
+	}
+
+	itemID, err := h.githubClient.AddIssueToProject(ctx, project.ID, event.Issue.NodeID)
+	if err != nil {
+		return err
+	}
+	logger.FromContext(ctx).Infof("added issue to project %s", project.Title)
+
+	values := triageValues(analysis)
+	for fieldName, source := range h.projectConfig.Fields {
+		field, ok := findProjectField(project.Fields, fieldName)
+		if !ok {
+			logger.FromContext(ctx).Warnf("project field %s not found or not a single select or iteration field", fieldName)
+			continue
+		}
+
+		value, ok := projectFieldValue(field, source, values, time.Now())
+		if !ok {
+			logger.FromContext(ctx).Warnf("no value of project field %s matches %s", fieldName, source)
+			continue
+		}
+
+		if err := h.githubClient.UpdateProjectItemField(ctx, project.ID, itemID, field.ID, value); err != nil {
+			return err
+		}
+		logger.FromContext(ctx).Infof("set project field %s from %s", fieldName, source)
+	}
+
+	return nil
+}
+
+// assignMilestone sets the milestone of the first matching milestone rule
+func (h *Helper) assignMilestone(ctx context.Context, event *GitHubEvent, analysis pkggithub.TriageAnalysis) error {
+	values := triageValues(analysis)
+
+	var title string
+	for _, field := range triageFields {
+		if t, ok := h.projectConfig.Milestones[field+":"+values[field]]; ok {
+			title = t
+			break
+		}
+	}
+	if title == "" {
+		logger.FromContext(ctx).Info("no milestone rule matched")
+		return nil
+	}
+
+	milestones, err := h.githubClient.GetOpenMilestones(ctx, event.Repository.Owner.Login, event.Repository.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, milestone := range milestones {
+		if strings.EqualFold(milestone.Title, title) {
+			if err := h.githubClient.SetIssueMilestone(ctx,
+				event.Repository.Owner.Login,
+				event.Repository.Name,
+				event.Issue.Number,
+				milestone.Number); err != nil {
+				return err
+			}
+			logger.FromContext(ctx).Infof("assigned milestone %s", milestone.Title)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("open milestone %s not found", title)
+}
+
+func findProjectField(fields []pkggithub.ProjectField, name string) (pkggithub.ProjectField, bool) {
+	for _, field := range fields {
+		if strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return pkggithub.ProjectField{}, false
+}
+
+// projectFieldValue resolves the value of a project field. Iteration fields accept @current and @next,
+// single select fields take the option named like the triage field value, e.g. "P1" for priority "p1".
+func projectFieldValue(field pkggithub.ProjectField, source string, values map[string]string, now time.Time) (pkggithub.ProjectFieldValue, bool) {
+	if source == currentIteration || source == nextIteration {
+		for i, iteration := range field.Iterations {
+			if now.Before(iteration.StartDate) {
+				// Between iterations the next one is the first upcoming iteration
+				if source == nextIteration {
+					return pkggithub.ProjectFieldValue{IterationID: iteration.ID}, true
+				}
+				continue
+			}
+			if !now.Before(iteration.StartDate.AddDate(0, 0, iteration.Duration)) {
+				continue
+			}
+			if source == nextIteration {
+				if i+1 >= len(field.Iterations) {
+					return pkggithub.ProjectFieldValue{}, false
+				}
+				iteration = field.Iterations[i+1]
+			}
+			return pkggithub.ProjectFieldValue{IterationID: iteration.ID}, true
+		}
+		return pkggithub.ProjectFieldValue{}, false
+	}
+
+	value := values[source]
+	if value == "" {
+		return pkggithub.ProjectFieldValue{}, false
+	}
+
+	for _, option := range field.Options {
+		if strings.EqualFold(option.Name, value) {
+			return pkggithub.ProjectFieldValue{SingleSelectOptionID: option.ID}, true
+		}
+	}
+	// Fall back to options that contain the value as a word, e.g. "🐛 Bug" for type "bug"
+	for _, option := range field.Options {
+		for _, word := range strings.Fields(option.Name) {
+			if strings.EqualFold(word, value) {
+				return pkggithub.ProjectFieldValue{SingleSelectOptionID: option.ID}, true
+			}
+		}
+	}
+
+	return pkggithub.ProjectFieldValue{}, false
+}
@@ -0,0 +1,79 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	pkggithub "github.com/workflowkit/issue-assistant/pkg/github"
+)
+
+func TestProjectFieldValue(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC) }
+	sprints := pkggithub.ProjectField{ID: "sprint", Name: "Sprint", Iterations: []pkggithub.ProjectIteration{
+		{ID: "it-1", Title: "Sprint 1", StartDate: day(2), Duration: 7},
+		{ID: "it-2", Title: "Sprint 2", StartDate: day(9), Duration: 7},
+		// A break of a week before the third sprint
+		{ID: "it-3", Title: "Sprint 3", StartDate: day(23), Duration: 7},
+	}}
+	priority := pkggithub.ProjectField{ID: "priority", Name: "Priority", Options: []pkggithub.ProjectFieldOption{
+		{ID: "opt-p0", Name: "P0"},
+		{ID: "opt-p1", Name: "P1"},
+		{ID: "opt-p10", Name: "P10 later"},
+	}}
+	kind := pkggithub.ProjectField{ID: "type", Name: "Type", Options: []pkggithub.ProjectFieldOption{
+		{ID: "opt-bug", Name: "🐛 Bug"},
+		{ID: "opt-feature", Name: "✨ Feature request"},
+	}}
+	values := map[string]string{"priority": "p1", "type": "bug", "severity": "low"}
+
+	tests := []struct {
+		name   string
+		field  pkggithub.ProjectField
+		source string
+		values map[string]string
+		now    time.Time
+		want   pkggithub.ProjectFieldValue
+	}{
+		{name: "current", field: sprints, source: "@current", now: day(4), want: pkggithub.ProjectFieldValue{IterationID: "it-1"}},
+		{name: "current on the first day", field: sprints, source: "@current", now: day(9), want: pkggithub.ProjectFieldValue{IterationID: "it-2"}},
+		{name: "current on the last day", field: sprints, source: "@current", now: day(15).Add(23 * time.Hour), want: pkggithub.ProjectFieldValue{IterationID: "it-2"}},
+		{name: "current during a break", field: sprints, source: "@current", now: day(18)},
+		{name: "current before the first", field: sprints, source: "@current", now: day(1)},
+		{name: "current after the last", field: sprints, source: "@current", now: day(30)},
+		{name: "next", field: sprints, source: "@next", now: day(4), want: pkggithub.ProjectFieldValue{IterationID: "it-2"}},
+		{name: "next across a break", field: sprints, source: "@next", now: day(10), want: pkggithub.ProjectFieldValue{IterationID: "it-3"}},
+		{name: "next during a break", field: sprints, source: "@next", now: day(18), want: pkggithub.ProjectFieldValue{IterationID: "it-3"}},
+		{name: "next before the first", field: sprints, source: "@next", now: day(1), want: pkggithub.ProjectFieldValue{IterationID: "it-1"}},
+		{name: "next in the last", field: sprints, source: "@next", now: day(25)},
+		{name: "iteration of a single select field", field: priority, source: "@current", now: day(4)},
+		{name: "option", field: priority, source: "priority", values: values, want: pkggithub.ProjectFieldValue{SingleSelectOptionID: "opt-p1"}},
+		{name: "option by word", field: kind, source: "type", values: values, want: pkggithub.ProjectFieldValue{SingleSelectOptionID: "opt-bug"}},
+		{name: "option by word ignoring case", field: kind, source: "type", values: map[string]string{"type": "Feature"}, want: pkggithub.ProjectFieldValue{SingleSelectOptionID: "opt-feature"}},
+		{name: "exact name before word", field: priority, source: "priority", values: map[string]string{"priority": "p10 later"}, want: pkggithub.ProjectFieldValue{SingleSelectOptionID: "opt-p10"}},
+		{name: "no matching option", field: priority, source: "severity", values: values},
+		{name: "no prefix match", field: priority, source: "priority", values: map[string]string{"priority": "p"}},
+		{name: "empty value", field: kind, source: "type", values: map[string]string{"type": ""}},
+		{name: "unknown source", field: kind, source: "milestone", values: values},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := projectFieldValue(tt.field, tt.source, tt.values, tt.now)
+			want := tt.want != pkggithub.ProjectFieldValue{}
+			if ok != want || got != tt.want {
+				t.Errorf("projectFieldValue() = %+v, %t, want %+v, %t", got, ok, tt.want, want)
+			}
+		})
+	}
+}
+
+func TestFindProjectField(t *testing.T) {
+	fields := []pkggithub.ProjectField{{ID: "status", Name: "Status"}, {ID: "sprint", Name: "Sprint"}}
+
+	if field, ok := findProjectField(fields, "sprint"); !ok || field.ID != "sprint" {
+		t.Errorf("findProjectField(sprint) = %+v, %t, want the Sprint field", field, ok)
+	}
+	if field, ok := findProjectField(fields, "Priority"); ok {
+		t.Errorf("findProjectField(Priority) = %+v, want not found", field)
+	}
+}
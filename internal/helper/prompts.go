@@ -0,0 +1,37 @@
+package helper
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/workflowkit/issue-assistant/pkg/ai"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// promptOverrideDir is where repositories keep their own prompt templates, named after the task (e.g. labels.tmpl)
+const promptOverrideDir = ".github/issue-assistant/prompts"
+
+// loadPromptOverrides replaces the embedded prompts with the templates of the repository
+func (h *Helper) loadPromptOverrides(ctx context.Context, event *GitHubEvent) {
+	files, err := h.githubClient.GetDirectoryFiles(ctx, event.Repository.Owner.Login, event.Repository.Name, promptOverrideDir)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("failed to get prompt overrides: %v", err)
+		return
+	}
+
+	for _, file := range files {
+		name := path.Base(file.Path)
+		if path.Ext(name) != ".tmpl" {
+			continue
+		}
+		task, err := ai.ParseTask(strings.TrimSuffix(name, ".tmpl"))
+		if err != nil {
+			logger.FromContext(ctx).Warnf("skipping prompt override %s: %v", file.Path, err)
+			continue
+		}
+		if err := h.prompts.Override(task, file.Content); err != nil {
+			logger.FromContext(ctx).Warnf("skipping prompt override %s: %v", file.Path, err)
+		}
+	}
+}
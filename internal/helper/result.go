@@ -0,0 +1,153 @@
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/workflowkit/issue-assistant/pkg/actions"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// Outcome is the outcome of a feature in a run
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "succeeded" // The feature completed its work
+	OutcomeSkipped   Outcome = "skipped"   // The feature had nothing to do
+	OutcomeFailed    Outcome = "failed"    // The feature failed
+)
+
+// FailurePolicy decides which feature failures fail the run
+type FailurePolicy string
+
+const (
+	FailOnAny   FailurePolicy = "any"   // Fail when any feature failed
+	FailOnAll   FailurePolicy = "all"   // Fail only when every feature that ran failed
+	FailOnNever FailurePolicy = "never" // Never fail because of features
+)
+
+// FeatureResult is the outcome of a feature with the reason it was skipped or failed
+type FeatureResult struct {
+	Feature Feature `json:"feature"`
+	Outcome Outcome `json:"outcome"`
+	Reason  string  `json:"reason,omitempty"`
+}
+
+// RunResult lists the outcome of every enabled feature in a run
+type RunResult struct {
+	Features []FeatureResult `json:"features"`
+}
+
+// add records the outcome of a feature from the error it returned
+func (r *RunResult) add(feature Feature, err error) {
+	result := FeatureResult{Feature: feature, Outcome: OutcomeSucceeded}
+	var skipped skipError
+	switch {
+	case errors.As(err, &skipped):
+		result.Outcome = OutcomeSkipped
+		result.Reason = skipped.reason
+	case err != nil:
+		result.Outcome = OutcomeFailed
+		result.Reason = err.Error()
+	}
+	r.Features = append(r.Features, result)
+}
+
+// skipAll records every feature as skipped for the same reason
+func (r *RunResult) skipAll(features []Feature, reason string) {
+	for _, feature := range features {
+		r.Features = append(r.Features, FeatureResult{Feature: feature, Outcome: OutcomeSkipped, Reason: reason})
+	}
+}
+
+// With returns the features with the given outcome
+func (r *RunResult) With(outcome Outcome) []Feature {
+	var features []Feature
+	for _, result := range r.Features {
+		if result.Outcome == outcome {
+			features = append(features, result.Feature)
+		}
+	}
+	return features
+}
+
+// Failed reports whether the run failed under the policy
+func (r *RunResult) Failed(policy FailurePolicy) bool {
+	failed := len(r.With(OutcomeFailed))
+	switch policy {
+	case FailOnNever:
+		return false
+	case FailOnAll:
+		return failed > 0 && failed == len(r.Features)-len(r.With(OutcomeSkipped))
+	default:
+		return failed > 0
+	}
+}
+
+// skipError reports that a feature had nothing to do
+type skipError struct {
+	reason string
+}
+
+func (e skipError) Error() string {
+	return e.reason
+}
+
+// skip returns the error of a feature skipped for the given reason
+func skip(reason string) error {
+	return skipError{reason: reason}
+}
+
+// reportResult logs the outcome of every feature and publishes the run result as step outputs and job summary
+func (h *Helper) reportResult(ctx context.Context, result *RunResult) {
+	for _, feature := range result.Features {
+		if feature.Reason != "" {
+			logger.FromContext(ctx).Infof("%s feature %s: %s", feature.Feature, feature.Outcome, feature.Reason)
+		} else {
+			logger.FromContext(ctx).Infof("%s feature %s", feature.Feature, feature.Outcome)
+		}
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("failed to encode run result: %v", err)
+		return
+	}
+
+	if err := actions.SetOutputs(map[string]string{
+		"result":          string(resultJSON),
+		"failed_features": joinFeatures(result.With(OutcomeFailed)),
+	}); err != nil {
+		logger.FromContext(ctx).Errorf("failed to set run result outputs: %v", err)
+	}
+
+	var summary strings.Builder
+	summary.WriteString("### Issue Assistant\n\n")
+	summary.WriteString("| Feature | Outcome | Reason |\n")
+	summary.WriteString("|---------|---------|--------|\n")
+	for _, feature := range result.Features {
+		summary.WriteString(fmt.Sprintf("| %s | %s | %s |\n",
+			feature.Feature, feature.Outcome, summaryCell(feature.Reason)))
+	}
+
+	if err := actions.AppendSummary(summary.String()); err != nil {
+		logger.FromContext(ctx).Errorf("failed to write run result summary: %v", err)
+	}
+}
+
+func joinFeatures(features []Feature) string {
+	names := make([]string, 0, len(features))
+	for _, feature := range features {
+		names = append(names, string(feature))
+	}
+	return strings.Join(names, ",")
+}
+
+// summaryCell escapes a value for a markdown table cell
+func summaryCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
@@ -0,0 +1,151 @@
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/workflowkit/issue-assistant/pkg/actions"
+	"github.com/workflowkit/issue-assistant/pkg/ai"
+	pkggithub "github.com/workflowkit/issue-assistant/pkg/github"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// triageFields are the triage result fields that can be mapped to labels
+var triageFields = []string{"type", "severity", "priority", "component", "reproducibility"}
+
+// processTriage handles structured triage feature
+func (h *Helper) processTriage(ctx context.Context, event *GitHubEvent) error {
+	analysis, err := h.getTriage(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to triage issue: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("triaged issue as type: %s, severity: %s, priority: %s, component: %s, reproducibility: %s",
+		analysis.Type, analysis.Severity, analysis.Priority, analysis.Component, analysis.Reproducibility)
+
+	if err := setTriageOutputs(analysis); err != nil {
+		logger.FromContext(ctx).Errorf("failed to set triage outputs: %v", err)
+	}
+
+	labels := h.triageLabels(analysis)
+	if len(labels) == 0 {
+		return skip("no triage labels mapped")
+	}
+
+	if err := h.githubClient.AddLabelsToIssue(ctx,
+		event.Repository.Owner.Login,
+		event.Repository.Name,
+		event.Issue.Number,
+		labels); err != nil {
+		return fmt.Errorf("failed to add triage labels to issue: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("successfully added triage labels: %s", strings.Join(labels, ", "))
+
+	return nil
+}
+
+// getTriage returns the triage result of the issue, analyzing it on first use
+// so that features building on the triage result can run without the triage feature.
+// The AI usage of the analysis is accounted to the triage feature, whichever feature needs it first.
+func (h *Helper) getTriage(ctx context.Context, event *GitHubEvent) (pkggithub.TriageAnalysis, error) {
+	inputs := inputsFromContext(ctx)
+	return inputs.triage.get(ctx, inputs.ctx, func(ctx context.Context) (pkggithub.TriageAnalysis, error) {
+		ctx, _ = ai.WithMetadata(ctx, string(FeatureTriage))
+		ctx = logger.WithFields(ctx, logger.F("feature", FeatureTriage))
+
+		paths, err := h.repositoryTree(ctx, event)
+		if err != nil {
+			return pkggithub.TriageAnalysis{}, err
+		}
+
+		components := repositoryComponents(paths, h.triageConfig.ComponentDepth)
+		return h.aiService.AnalyzeTriage(ctx, event.Issue.Title, event.Issue.Body, components)
+	})
+}
+
+// triageLabels maps the triage result to labels using the configured label map
+func (h *Helper) triageLabels(analysis pkggithub.TriageAnalysis) []string {
+	values := triageValues(analysis)
+
+	seen := make(map[string]bool)
+	var labels []string
+	for _, field := range triageFields {
+		label, ok := h.triageConfig.Labels[field+":"+values[field]]
+		if !ok || seen[label] {
+			continue
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// setTriageOutputs exposes the triage result as action outputs
+func setTriageOutputs(analysis pkggithub.TriageAnalysis) error {
+	encoded, err := json.Marshal(analysis)
+	if err != nil {
+		return fmt.Errorf("failed to encode triage result: %w", err)
+	}
+
+	outputs := map[string]string{
+		"triage":         string(encoded),
+		"triage_summary": analysis.Summary,
+	}
+	for field, value := range triageValues(analysis) {
+		outputs["triage_"+field] = value
+	}
+
+	return actions.SetOutputs(outputs)
+}
+
+// triageValues returns the label-mappable fields of the triage result by name
+func triageValues(analysis pkggithub.TriageAnalysis) map[string]string {
+	return map[string]string{
+		"type":            analysis.Type,
+		"severity":        analysis.Severity,
+		"priority":        analysis.Priority,
+		"component":       analysis.Component,
+		"reproducibility": analysis.Reproducibility,
+	}
+}
+
+// repositoryComponents derives components from the directories of the repository files,
+// up to the given depth (e.g. depth 2 turns "pkg/ai/openai.go" into "pkg" and "pkg/ai")
+func repositoryComponents(paths []string, depth int) []string {
+	excluded := pkggithub.DefaultFileFilter().ExcludedPaths
+
+	seen := make(map[string]bool)
+	var components []string
+	for _, p := range paths {
+		if isExcludedPath(p, excluded) {
+			continue
+		}
+
+		parts := strings.Split(path.Dir(p), "/")
+		for i := 1; i <= len(parts) && i <= depth; i++ {
+			dir := strings.Join(parts[:i], "/")
+			if dir == "." || strings.HasPrefix(parts[0], ".") || seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			components = append(components, dir)
+		}
+	}
+
+	sort.Strings(components)
+	return components
+}
+
+func isExcludedPath(p string, excluded []string) bool {
+	for _, e := range excluded {
+		if strings.Contains(p, e) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+
+	pkggithub "github.com/workflowkit/issue-assistant/pkg/github"
+)
+
+func TestTriageLabels(t *testing.T) {
+	labels := map[string]string{
+		"type:bug":          "bug",
+		"type:feature":      "enhancement",
+		"severity:critical": "urgent",
+		"priority:p0":       "urgent",
+		"component:pkg/ai":  "area/ai",
+	}
+
+	tests := []struct {
+		name     string
+		analysis pkggithub.TriageAnalysis
+		want     []string
+	}{
+		{
+			name:     "mapped in field order",
+			analysis: pkggithub.TriageAnalysis{Type: "bug", Component: "pkg/ai", Severity: "low"},
+			want:     []string{"bug", "area/ai"},
+		},
+		{
+			name:     "same label once",
+			analysis: pkggithub.TriageAnalysis{Type: "feature", Severity: "critical", Priority: "p0"},
+			want:     []string{"enhancement", "urgent"},
+		},
+		{
+			name:     "values are matched exactly",
+			analysis: pkggithub.TriageAnalysis{Type: "Bug", Component: "pkg"},
+		},
+		{
+			name: "empty result",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Helper{triageConfig: TriageConfig{Labels: labels}}
+			if got := h.triageLabels(tt.analysis); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("triageLabels() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepositoryComponents(t *testing.T) {
+	paths := []string{
+		"main.go",
+		"README.md",
+		"pkg/ai/openai.go",
+		"pkg/ai/prompts/labels.tmpl",
+		"pkg/github/client.go",
+		"internal/helper/helper.go",
+		"vendor/github.com/x/y.go",
+		".github/workflows/ci.yml",
+		"node_modules/left-pad/index.js",
+	}
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, nil},
+		{1, []string{"internal", "pkg"}},
+		{2, []string{"internal", "internal/helper", "pkg", "pkg/ai", "pkg/github"}},
+		{3, []string{"internal", "internal/helper", "pkg", "pkg/ai", "pkg/ai/prompts", "pkg/github"}},
+	}
+
+	for _, tt := range tests {
+		if got := repositoryComponents(paths, tt.depth); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("repositoryComponents(depth %d) = %q, want %q", tt.depth, got, tt.want)
+		}
+	}
+}
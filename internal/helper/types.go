@@ -1,17 +1,217 @@
 package helper
 
+import (
+	"strings"
+	"time"
+
+	"github.com/workflowkit/issue-assistant/pkg/ai"
+)
+
 // GitHubEvent represents the structure of a GitHub issue event
 type GitHubEvent struct {
 	Action string `json:"action"`
 	Issue  struct {
 		Number int    `json:"number"`
+		NodeID string `json:"node_id"`
 		Title  string `json:"title"`
 		Body   string `json:"body"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		User struct {
+			Login string `json:"login"`
+			Type  string `json:"type"`
+		} `json:"user"`
+		AuthorAssociation string `json:"author_association"`
 	} `json:"issue"`
 	Repository struct {
 		Owner struct {
 			Login string `json:"login"`
 		} `json:"owner"`
 		Name string `json:"name"`
+		Fork bool   `json:"fork"`
 	} `json:"repository"`
 }
+
+// labelNames returns the names of the labels on the issue
+func (e *GitHubEvent) labelNames() []string {
+	names := make([]string, 0, len(e.Issue.Labels))
+	for _, label := range e.Issue.Labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+// hasLabel reports whether the issue carries the given label
+func (e *GitHubEvent) hasLabel(name string) bool {
+	for _, label := range e.Issue.Labels {
+		if strings.EqualFold(label.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// DuplicateConfig holds the settings of the duplicate detection feature
+type DuplicateConfig struct {
+	// MaxCandidates is the number of most similar issues sent to the AI for confirmation
+	MaxCandidates int
+	// MinSimilarity is the local similarity score (0.0-1.0) a candidate needs to be considered
+	MinSimilarity float64
+	// ClosedWithinDays limits closed candidates to issues closed within this many days
+	ClosedWithinDays int
+	// Label is applied to the issue when a duplicate is confirmed, empty disables labeling
+	Label string
+	// LabelThreshold is the AI confidence (0.0-1.0) required before Label is applied
+	LabelThreshold float64
+}
+
+// DefaultDuplicateConfig returns the default duplicate detection configuration
+func DefaultDuplicateConfig() DuplicateConfig {
+	return DuplicateConfig{
+		MaxCandidates:    5,
+		MinSimilarity:    0.2,
+		ClosedWithinDays: 90,
+		LabelThreshold:   0.85,
+	}
+}
+
+// MissingInfoConfig holds the settings of the missing information feature
+type MissingInfoConfig struct {
+	// Label is applied while required template sections are missing, empty disables labeling
+	Label string
+}
+
+// DefaultMissingInfoConfig returns the default missing information configuration
+func DefaultMissingInfoConfig() MissingInfoConfig {
+	return MissingInfoConfig{
+		Label: "needs-info",
+	}
+}
+
+// PolicyConfig holds the rules deciding which issues are processed, so that not everyone
+// who can open an issue can trigger paid AI requests
+type PolicyConfig struct {
+	// AllowBots processes issues opened by bots, e.g. dependabot[bot]
+	AllowBots bool
+	// AuthorAssociations limits processing to authors with these associations
+	// (e.g. "OWNER", "MEMBER", "COLLABORATOR", "CONTRIBUTOR", "NONE"), empty allows all
+	AuthorAssociations []string
+	// DenyAuthors lists logins whose issues are never processed
+	DenyAuthors []string
+	// RequireLabels limits processing to issues carrying at least one of these labels
+	RequireLabels []string
+	// SkipLabels skips issues carrying any of these labels
+	SkipLabels []string
+	// AllowForks processes issues of repositories that are forks
+	AllowForks bool
+	// MinAccountAgeDays skips authors whose account is younger, 0 disables the check
+	MinAccountAgeDays int
+	// MaxIssuesOpenedPerDay skips authors who opened this many other issues in the repository
+	// within the last 24 hours, 0 disables the limit. It counts opened issues, not runs,
+	// and is only checked when an issue is opened.
+	MaxIssuesOpenedPerDay int
+	// SkipOnError skips the issue when a rule cannot be checked, e.g. because the author
+	// lookup failed or the search is rate limited. By default such rules let the issue pass.
+	SkipOnError bool
+}
+
+// InjectionConfig holds the settings of prompt injection detection
+type InjectionConfig struct {
+	// Label is applied to issues suspected of prompt injection, empty disables labeling
+	Label string
+	// Skip skips all features for suspected issues instead of running them on hardened prompts
+	Skip bool
+}
+
+// DefaultInjectionConfig returns the default prompt injection detection configuration
+func DefaultInjectionConfig() InjectionConfig {
+	return InjectionConfig{
+		Label: "suspected-prompt-injection",
+	}
+}
+
+// TriageConfig holds the settings of the structured triage feature
+type TriageConfig struct {
+	// Labels maps triage values to labels, keyed by "field:value" (e.g. "type:bug", "severity:critical")
+	Labels map[string]string
+	// ComponentDepth is the directory depth used to derive components from repository paths
+	ComponentDepth int
+}
+
+// DefaultTriageConfig returns the default triage configuration
+func DefaultTriageConfig() TriageConfig {
+	return TriageConfig{
+		Labels:         map[string]string{},
+		ComponentDepth: 2,
+	}
+}
+
+// AssigneeConfig holds the settings of the suggested assignees feature
+type AssigneeConfig struct {
+	// Assign assigns the best ranked user to the issue
+	Assign bool
+	// Mention @-mentions the suggested users and teams in the comment
+	Mention bool
+	// OptOut lists logins that are never suggested
+	OptOut []string
+	// MaxSuggestions caps the number of suggested (and mentioned) users and teams
+	MaxSuggestions int
+	// CommitDays is how far back commit history is inspected
+	CommitDays int
+}
+
+// DefaultAssigneeConfig returns the default suggested assignees configuration
+func DefaultAssigneeConfig() AssigneeConfig {
+	return AssigneeConfig{
+		MaxSuggestions: 3,
+		CommitDays:     180,
+	}
+}
+
+// ProjectConfig holds the settings of the project and milestone placement feature
+type ProjectConfig struct {
+	// Owner is the organization or user owning the project, defaults to the repository owner
+	Owner string
+	// Number is the project number, 0 disables project placement
+	Number int
+	// Fields maps project field names to the triage field (e.g. "priority", "component") that sets them,
+	// iteration fields take "@current" or "@next"
+	Fields map[string]string
+	// Milestones maps triage values to milestone titles, keyed by "field:value" (e.g. "severity:critical")
+	Milestones map[string]string
+}
+
+// CostConfig holds the configuration of AI cost accounting
+type CostConfig struct {
+	// Prices overrides the price of models in USD per million tokens
+	Prices map[string]ai.Price
+	// DefaultPrice is the price of models without a price, nil counts them as free.
+	// With a budget, every model needs a price or the default price.
+	DefaultPrice *ai.Price
+	// Budget is the maximum cost of a run in USD, requests that could exceed it are not made. 0 disables the budget.
+	Budget float64
+}
+
+// TimeoutConfig holds the time limits of features
+type TimeoutConfig struct {
+	// Default is the time limit of a feature without an override
+	Default time.Duration
+	// Features overrides the time limit per feature
+	Features map[Feature]time.Duration
+}
+
+// DefaultTimeoutConfig returns the default feature time limits
+func DefaultTimeoutConfig() TimeoutConfig {
+	return TimeoutConfig{
+		Default: 5 * time.Minute,
+	}
+}
+
+// timeout returns the time limit of a feature
+func (c TimeoutConfig) timeout(feature Feature) time.Duration {
+	if timeout, ok := c.Features[feature]; ok {
+		return timeout
+	}
+	return c.Default
+}
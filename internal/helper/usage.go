@@ -0,0 +1,80 @@
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/workflowkit/issue-assistant/pkg/actions"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// reportUsage logs the token usage and cost of the run and publishes it as step outputs and job summary
+func (h *Helper) reportUsage(ctx context.Context) {
+	total := h.accountant.Total()
+	byFeature := h.accountant.ByFeature()
+	features := h.accountant.Features()
+
+	for _, feature := range features {
+		usage := byFeature[feature]
+		logger.FromContext(ctx).Infof("AI usage of %s: %d requests, %d prompt tokens, %d completion tokens, $%.4f",
+			feature, usage.Requests, usage.PromptTokens, usage.CompletionTokens, usage.Cost)
+	}
+	logger.FromContext(ctx).Infof("AI usage of run: %d requests, %d prompt tokens, %d completion tokens, $%.4f",
+		total.Requests, total.PromptTokens, total.CompletionTokens, total.Cost)
+
+	usageJSON, err := json.Marshal(byFeature)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("failed to encode AI usage: %v", err)
+		return
+	}
+
+	if err := actions.SetOutputs(map[string]string{
+		"ai_prompt_tokens":     fmt.Sprint(total.PromptTokens),
+		"ai_completion_tokens": fmt.Sprint(total.CompletionTokens),
+		"ai_cost":              fmt.Sprintf("%.6f", total.Cost),
+		"ai_usage":             string(usageJSON),
+	}); err != nil {
+		logger.FromContext(ctx).Errorf("failed to set AI usage outputs: %v", err)
+	}
+
+	if total.Requests == 0 {
+		return
+	}
+
+	var summary strings.Builder
+	summary.WriteString("### AI Usage\n\n")
+	summary.WriteString("| Feature | Requests | Prompt Tokens | Completion Tokens | Cost |\n")
+	summary.WriteString("|---------|----------|---------------|-------------------|------|\n")
+	for _, feature := range features {
+		usage := byFeature[feature]
+		summary.WriteString(fmt.Sprintf("| %s | %d | %d | %d | $%.4f |\n",
+			feature, usage.Requests, usage.PromptTokens, usage.CompletionTokens, usage.Cost))
+	}
+	summary.WriteString(fmt.Sprintf("| **Total** | %d | %d | %d | $%.4f |\n",
+		total.Requests, total.PromptTokens, total.CompletionTokens, total.Cost))
+
+	if err := actions.AppendSummary(summary.String()); err != nil {
+		logger.FromContext(ctx).Errorf("failed to write AI usage summary: %v", err)
+	}
+}
+
+// reportGitHubUsage logs the GitHub API calls of the run
+func (h *Helper) reportGitHubUsage(ctx context.Context) {
+	if h.githubMetrics == nil {
+		return
+	}
+
+	metrics := h.githubMetrics.Metrics()
+	for _, method := range h.githubMetrics.Methods() {
+		call := metrics[method]
+		logger.FromContext(ctx).Debugf("GitHub API usage of %s: %d calls, %d errors, %s",
+			method, call.Calls, call.Errors, call.Duration.Round(time.Millisecond))
+	}
+
+	total := h.githubMetrics.Total()
+	logger.FromContext(ctx).Infof("GitHub API usage of run: %d calls, %d errors, %s",
+		total.Calls, total.Errors, total.Duration.Round(time.Millisecond))
+}
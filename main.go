@@ -2,48 +2,57 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 
 	"github.com/workflowkit/issue-assistant/internal/helper"
+	"github.com/workflowkit/issue-assistant/pkg/ai"
+	"github.com/workflowkit/issue-assistant/pkg/github"
 	"github.com/workflowkit/issue-assistant/pkg/logger"
 )
 
 func main() {
-	ctx := context.Background()
+	// The runner sends SIGTERM when the job is cancelled or times out
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
 
-	logger.SetLogger(logger.ZapLogger)
+	// Secrets and personal data are removed from the log and from prompts
+	redactor := newRedactor()
+	secret := func(value string) {
+		logger.Mask(value)
+		redactor.Add(value)
+	}
+
+	// Workflow commands inside GitHub Actions, colored console output elsewhere
+	logger.SetLogger(logger.Detect(), append(logOptions(), logger.WithRedaction(redactor.Redact))...)
 	logger.Log.Info("starting issue assistant")
 
+	// Correlates the log lines of a workflow run
+	if runID := os.Getenv("GITHUB_RUN_ID"); runID != "" {
+		ctx = logger.WithFields(ctx, logger.F("run_id", runID), logger.F("run_attempt", os.Getenv("GITHUB_RUN_ATTEMPT")))
+	}
+
 	token := os.Getenv("GITHUB_TOKEN")
 	if token == "" {
-		logger.Log.Fatal("GITHUB_TOKEN is required")
+		exitf(exitConfiguration, "GITHUB_TOKEN is required")
 	}
+	secret(token)
 
-	aiType := os.Getenv("AI_TYPE")
-	if aiType == "" {
-		logger.Log.Fatal("AI_TYPE is required")
+	if os.Getenv("AI_TYPE") == "" {
+		exitf(exitConfiguration, "AI_TYPE is required")
 	}
+	aiType := parseAIType("AI_TYPE", os.Getenv("AI_TYPE"))
 
-	var apiKey string
-	if aiType == "openai" {
-		openAIKey := os.Getenv("OPENAI_API_KEY")
-		if openAIKey == "" {
-			logger.Log.Fatal("OPENAI_API_KEY is required when using OpenAI")
-		}
-		apiKey = openAIKey
-	} else if aiType == "claude" {
-		claudeKey := os.Getenv("CLAUDE_API_KEY")
-		if claudeKey == "" {
-			logger.Log.Fatal("CLAUDE_API_KEY is required when using Claude")
-		}
-		apiKey = claudeKey
-	} else {
-		logger.Log.Fatal("AI_TYPE must be either 'openai' or 'claude'")
-	}
+	apiKey, aiOptions := aiConfig(aiType)
+	secret(apiKey)
 
 	eventPath := os.Getenv("GITHUB_EVENT_PATH")
 	if eventPath == "" {
-		logger.Log.Fatal("GITHUB_EVENT_PATH is required")
+		exitf(exitConfiguration, "GITHUB_EVENT_PATH is required")
 	}
 
 	// Convert boolean flags to feature array
@@ -54,20 +63,196 @@ func main() {
 	if os.Getenv("ENABLE_LABEL") == "true" {
 		features = append(features, helper.FeatureLabel)
 	}
+	if os.Getenv("ENABLE_DUPLICATE") == "true" {
+		features = append(features, helper.FeatureDuplicate)
+	}
+	if os.Getenv("ENABLE_MISSING_INFO") == "true" {
+		features = append(features, helper.FeatureMissingInfo)
+	}
+	if os.Getenv("ENABLE_TRIAGE") == "true" {
+		features = append(features, helper.FeatureTriage)
+	}
+	if os.Getenv("ENABLE_ASSIGNEE") == "true" {
+		features = append(features, helper.FeatureAssignee)
+	}
+	if os.Getenv("ENABLE_PROJECT") == "true" {
+		features = append(features, helper.FeatureProject)
+	}
 
 	if len(features) == 0 {
-		logger.Log.Fatal("at least one feature must be enabled")
+		exitf(exitConfiguration, "at least one feature must be enabled")
+	}
+
+	duplicateConfig := helper.DefaultDuplicateConfig()
+	duplicateConfig.Label = os.Getenv("DUPLICATE_LABEL")
+	if threshold := os.Getenv("DUPLICATE_THRESHOLD"); threshold != "" {
+		value, err := strconv.ParseFloat(threshold, 64)
+		if err != nil {
+			exitf(exitConfiguration, "DUPLICATE_THRESHOLD must be a number: %v", err)
+		}
+		duplicateConfig.LabelThreshold = value
+	}
+
+	missingInfoConfig := helper.DefaultMissingInfoConfig()
+	if label, ok := os.LookupEnv("MISSING_INFO_LABEL"); ok {
+		missingInfoConfig.Label = label
+	}
+
+	injectionConfig := helper.DefaultInjectionConfig()
+	if label, ok := os.LookupEnv("INJECTION_LABEL"); ok {
+		injectionConfig.Label = label
+	}
+	injectionConfig.Skip = os.Getenv("INJECTION_SKIP") == "true"
+
+	triageConfig := helper.DefaultTriageConfig()
+	if labels := os.Getenv("TRIAGE_LABELS"); labels != "" {
+		mapping, err := parseMapping(labels)
+		if err != nil {
+			exitf(exitConfiguration, "TRIAGE_LABELS is invalid: %v", err)
+		}
+		triageConfig.Labels = mapping
+	}
+
+	assigneeConfig := helper.DefaultAssigneeConfig()
+	assigneeConfig.Assign = os.Getenv("ASSIGNEE_ASSIGN") == "true"
+	assigneeConfig.Mention = os.Getenv("ASSIGNEE_MENTION") == "true"
+	assigneeConfig.OptOut = strings.FieldsFunc(os.Getenv("ASSIGNEE_OPT_OUT"), func(r rune) bool {
+		return r == ',' || r == '\n' || r == ' '
+	})
+	if maxMentions := os.Getenv("ASSIGNEE_MAX_MENTIONS"); maxMentions != "" {
+		value, err := strconv.Atoi(maxMentions)
+		if err != nil {
+			exitf(exitConfiguration, "ASSIGNEE_MAX_MENTIONS must be a number: %v", err)
+		}
+		assigneeConfig.MaxSuggestions = value
+	}
+
+	projectConfig := helper.ProjectConfig{Owner: os.Getenv("PROJECT_OWNER")}
+	if number := os.Getenv("PROJECT_NUMBER"); number != "" {
+		value, err := strconv.Atoi(number)
+		if err != nil {
+			exitf(exitConfiguration, "PROJECT_NUMBER must be a number: %v", err)
+		}
+		projectConfig.Number = value
+	}
+	if fields := os.Getenv("PROJECT_FIELDS"); fields != "" {
+		mapping, err := parseMapping(fields)
+		if err != nil {
+			exitf(exitConfiguration, "PROJECT_FIELDS is invalid: %v", err)
+		}
+		projectConfig.Fields = mapping
+	}
+	if rules := os.Getenv("MILESTONE_RULES"); rules != "" {
+		mapping, err := parseMapping(rules)
+		if err != nil {
+			exitf(exitConfiguration, "MILESTONE_RULES is invalid: %v", err)
+		}
+		projectConfig.Milestones = mapping
+	}
+
+	costConfig := helper.CostConfig{}
+	if prices := os.Getenv("AI_PRICES"); prices != "" {
+		costConfig.Prices = parsePrices(prices)
+	}
+	if price := os.Getenv("AI_DEFAULT_PRICE"); price != "" {
+		defaultPrice, err := ai.ParsePrice(price)
+		if err != nil {
+			exitf(exitConfiguration, "AI_DEFAULT_PRICE is invalid: %v", err)
+		}
+		costConfig.DefaultPrice = &defaultPrice
+	}
+	if budget := os.Getenv("AI_BUDGET"); budget != "" {
+		costConfig.Budget = parseFloat("AI_BUDGET", budget)
 	}
 
-	hpr, err := helper.NewHelper(
-		helper.WithGitHubClient(token),
-		helper.WithAIService(aiType, apiKey),
+	timeoutConfig := helper.DefaultTimeoutConfig()
+	if timeout := os.Getenv("FEATURE_TIMEOUT"); timeout != "" {
+		timeoutConfig.Default = parseDuration("FEATURE_TIMEOUT", timeout)
+	}
+	if timeouts := os.Getenv("FEATURE_TIMEOUTS"); timeouts != "" {
+		timeoutConfig.Features = parseTimeouts(timeouts)
+	}
+
+	// Set by the runner, pointing at GitHub Enterprise Server when the workflow runs there
+	var githubOptions []github.ClientOption
+	if apiURL := os.Getenv("GITHUB_API_URL"); apiURL != "" {
+		githubOptions = append(githubOptions, github.WithBaseURL(apiURL))
+	}
+	if graphqlURL := os.Getenv("GITHUB_GRAPHQL_URL"); graphqlURL != "" {
+		githubOptions = append(githubOptions, github.WithGraphQLURL(graphqlURL))
+	}
+
+	helperOptions := []helper.Option{
+		helper.WithGitHubClient(token, githubOptions...),
+		helper.WithAIService(string(aiType), apiKey, aiOptions...),
 		helper.WithGitHubEventPath(eventPath),
 		helper.WithFeatures(features),
-	)
+		helper.WithDuplicateConfig(duplicateConfig),
+		helper.WithMissingInfoConfig(missingInfoConfig),
+		helper.WithPolicyConfig(policyConfig()),
+		helper.WithInjectionConfig(injectionConfig),
+		helper.WithTriageConfig(triageConfig),
+		helper.WithAssigneeConfig(assigneeConfig),
+		helper.WithProjectConfig(projectConfig),
+		helper.WithCostConfig(costConfig),
+		helper.WithTimeoutConfig(timeoutConfig),
+		helper.WithDryRun(os.Getenv("DRY_RUN") == "true"),
+		helper.WithRedactor(redactor),
+	}
+	if failOn := os.Getenv("FAIL_ON"); failOn != "" {
+		helperOptions = append(helperOptions, helper.WithFailurePolicy(helper.FailurePolicy(failOn)))
+	}
+
+	// Providers tried in order when the previous ones fail
+	for _, name := range strings.FieldsFunc(os.Getenv("AI_FALLBACK"), func(r rune) bool { return r == ',' || r == ' ' }) {
+		fallbackType := parseAIType("AI_FALLBACK", name)
+		fallbackKey, fallbackOptions := aiConfig(fallbackType)
+		secret(fallbackKey)
+		helperOptions = append(helperOptions, helper.WithFallbackAIService(string(fallbackType), fallbackKey, fallbackOptions...))
+	}
+
+	hpr, err := helper.NewHelper(helperOptions...)
 	if err != nil {
-		logger.Log.Fatalf("failed to create helper: %v", err)
+		exitf(exitConfiguration, "failed to create helper: %v", err)
+	}
+
+	if _, err := hpr.Help(ctx); err != nil {
+		exitf(exitCode(err), "failed to process issue: %v", err)
+	}
+
+	if err := logger.Close(); err != nil {
+		logger.Log.Errorf("failed to write log summary: %v", err)
 	}
+}
+
+// Exit codes of the issue assistant, so workflows can tell failures apart
+const (
+	exitFailure        = 1
+	exitConfiguration  = 2
+	exitMalformedEvent = 3
+	exitAuthentication = 4
+	exitCancelled      = 5
+)
+
+// exitCode returns the exit code of an error returned by the helper
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, helper.ErrAuthentication):
+		return exitAuthentication
+	case errors.Is(err, helper.ErrMalformedEvent):
+		return exitMalformedEvent
+	case errors.Is(err, ai.ErrUnsupportedProvider):
+		return exitConfiguration
+	case errors.Is(err, context.Canceled):
+		return exitCancelled
+	default:
+		return exitFailure
+	}
+}
 
-	hpr.Help(ctx)
+// exitf logs the error and exits with the given code
+func exitf(code int, msg string, args ...interface{}) {
+	logger.Log.Errorf(msg, args...)
+	_ = logger.Close()
+	os.Exit(code)
 }
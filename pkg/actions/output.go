@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SetOutput sets a step output by appending it to the $GITHUB_OUTPUT file.
+// It does nothing when not running inside GitHub Actions.
+func SetOutput(name, value string) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return nil
+	}
+
+	return appendToFile(path, formatOutput(name, value))
+}
+
+// SetOutputs sets several step outputs at once
+func SetOutputs(outputs map[string]string) error {
+	for name, value := range outputs {
+		if err := SetOutput(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatOutput formats an output using the heredoc syntax, which supports multiline values
+func formatOutput(name, value string) string {
+	delimiter := "ghadelimiter_" + randomHex()
+	for strings.Contains(value, delimiter) {
+		delimiter = "ghadelimiter_" + randomHex()
+	}
+	return fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter)
+}
+
+func appendToFile(path, content string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func randomHex() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,14 @@
+package actions
+
+import "os"
+
+// AppendSummary appends markdown to the job summary by writing it to the $GITHUB_STEP_SUMMARY file.
+// It does nothing when not running inside GitHub Actions.
+func AppendSummary(markdown string) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+
+	return appendToFile(path, markdown+"\n")
+}
@@ -0,0 +1,111 @@
+package aitest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/workflowkit/issue-assistant/pkg/ai"
+	"github.com/workflowkit/issue-assistant/pkg/github"
+)
+
+// ErrUnscripted is returned by a Mock method that has no scripted response left
+var ErrUnscripted = errors.New("no scripted response")
+
+// Response is a scripted result of an AI service method
+type Response[T any] struct {
+	Value T
+	Err   error
+}
+
+// CodeAnswer is the result of AnalyzeCode
+type CodeAnswer struct {
+	Answer     string
+	Confidence float64
+}
+
+// Call is a recorded call of a Mock method
+type Call struct {
+	// Method is the name of the called method, e.g. "AnalyzeLabels"
+	Method string
+	// Title is the issue title, or the question for AnalyzeCode
+	Title string
+	// Body is the issue body, empty for AnalyzeCode
+	Body string
+}
+
+// Mock is an AI service that returns scripted responses in order and records its calls.
+// Each method consumes the next response of its script and fails with ErrUnscripted
+// when the script is used up.
+type Mock struct {
+	Code       []Response[CodeAnswer]
+	Labels     []Response[github.LabelAnalysis]
+	Duplicates []Response[github.DuplicateAnalysis]
+	Triage     []Response[github.TriageAnalysis]
+	Relevance  []Response[[]string]
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ ai.AIService = (*Mock)(nil)
+
+// Calls returns the recorded calls in order
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the recorded calls of a method
+func (m *Mock) CallsTo(method string) []Call {
+	var calls []Call
+	for _, call := range m.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// next records a call and pops the next response of the script
+func next[T any](m *Mock, ctx context.Context, script *[]Response[T], call Call) (T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, call)
+
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	if len(*script) == 0 {
+		return zero, fmt.Errorf("%s: %w", call.Method, ErrUnscripted)
+	}
+
+	response := (*script)[0]
+	*script = (*script)[1:]
+	return response.Value, response.Err
+}
+
+func (m *Mock) AnalyzeCode(ctx context.Context, question string, files []github.GitHubFile) (string, float64, error) {
+	answer, err := next(m, ctx, &m.Code, Call{Method: "AnalyzeCode", Title: question})
+	return answer.Answer, answer.Confidence, err
+}
+
+func (m *Mock) AnalyzeLabels(ctx context.Context, title, body string, availableLabels string) (github.LabelAnalysis, error) {
+	return next(m, ctx, &m.Labels, Call{Method: "AnalyzeLabels", Title: title, Body: body})
+}
+
+func (m *Mock) AnalyzeDuplicates(ctx context.Context, title, body string, candidates []github.DuplicateCandidate) (github.DuplicateAnalysis, error) {
+	return next(m, ctx, &m.Duplicates, Call{Method: "AnalyzeDuplicates", Title: title, Body: body})
+}
+
+func (m *Mock) AnalyzeTriage(ctx context.Context, title, body string, components []string) (github.TriageAnalysis, error) {
+	return next(m, ctx, &m.Triage, Call{Method: "AnalyzeTriage", Title: title, Body: body})
+}
+
+func (m *Mock) AnalyzeRelevantFiles(ctx context.Context, title, body string, paths []string) ([]string, error) {
+	return next(m, ctx, &m.Relevance, Call{Method: "AnalyzeRelevantFiles", Title: title, Body: body})
+}
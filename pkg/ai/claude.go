@@ -2,9 +2,7 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
@@ -12,192 +10,153 @@ import (
 	"github.com/workflowkit/issue-assistant/pkg/logger"
 )
 
+// defaultClaudeModel is the model used when none is configured
+const defaultClaudeModel = "claude-3-5-haiku-20241022"
+
 type Claude struct {
-	client *anthropic.Client
+	client  *anthropic.Client
+	engine  requestEngine
+	models  modelSelector
+	prompts *PromptRegistry
 }
 
-func newClaudeService(apiKey string) AIService {
+func newClaudeService(apiKey string, cfg config) AIService {
 	return &Claude{
-		client: anthropic.NewClient(option.WithAPIKey(apiKey)),
+		client: anthropic.NewClient(
+			option.WithAPIKey(apiKey),
+			option.WithHTTPClient(newHTTPClient(cfg)),
+			// Retries are handled by the request engine
+			option.WithMaxRetries(0),
+		),
+		engine:  newRequestEngine("Claude", cfg),
+		models:  newModelSelector(cfg, defaultClaudeModel),
+		prompts: cfg.prompts,
 	}
 }
 
-// makeRequest is a helper function to make Claude API requests with retries
-func (c *Claude) makeRequest(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
-	const maxRetries = 3
-	var lastErr error
-	baseTemperature := float64(0.1)
-
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		logger.Log.Debugf("making Claude request: %d with temperature: %.2f", attempt+1, baseTemperature)
+// makeRequest sends a messages request through the shared request engine. Structured
+// output is enforced by forcing the model to call a tool whose input schema is the response schema.
+func (c *Claude) makeRequest(ctx context.Context, task Task, format responseSchema, p prompt) (string, error) {
+	model, maxTokens, temperature := c.models.resolve(task)
+	logger.FromContext(ctx).Debugf("using Claude model: %s temperature: %.2f", model, temperature)
 
+	return c.engine.do(ctx, request{model: model, maxTokens: maxTokens, prompt: p, schema: format.schema}, func(ctx context.Context) (string, tokens, error) {
 		resp, err := c.client.Messages.New(ctx, anthropic.MessageNewParams{
-			Model:     anthropic.F("claude-3-5-haiku-20241022"),
-			MaxTokens: anthropic.F(int64(2000)),
+			Model:     anthropic.F(model),
+			MaxTokens: anthropic.F(int64(maxTokens)),
 			System: anthropic.F([]anthropic.TextBlockParam{
 				{
 					Type: anthropic.F(anthropic.TextBlockParamTypeText),
-					Text: anthropic.F(systemPrompt),
+					Text: anthropic.F(p.system),
 				},
 			}),
 			Messages: anthropic.F([]anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(userPrompt)),
+				anthropic.NewUserMessage(anthropic.NewTextBlock(p.user)),
+			}),
+			Temperature: anthropic.F(temperature),
+			Tools: anthropic.F([]anthropic.ToolParam{
+				{
+					Name:        anthropic.F(format.name),
+					Description: anthropic.F("Report the result of the analysis"),
+					InputSchema: anthropic.F[interface{}](format.schema),
+				},
+			}),
+			ToolChoice: anthropic.F[anthropic.ToolChoiceUnionParam](anthropic.ToolChoiceToolParam{
+				Name: anthropic.F(format.name),
+				Type: anthropic.F(anthropic.ToolChoiceToolTypeTool),
 			}),
-			Temperature: anthropic.F(baseTemperature),
 		})
-
 		if err != nil {
-			logger.Log.Warnf("Claude request failed attempt: %d: %v", attempt+1, err)
-			lastErr = fmt.Errorf("Claude API error: %w", err)
-			baseTemperature -= 0.02
-			continue
-		}
-
-		if len(resp.Content) == 0 {
-			lastErr = fmt.Errorf("empty response from Claude")
-			baseTemperature -= 0.02
-			continue
+			return "", tokens{}, err
 		}
 
-		content := resp.Content[0].Text
-
-		// Clean and validate the response
-		content = strings.TrimSpace(content)
-		// Remove any markdown code block wrapping if exists
-		content = strings.TrimPrefix(content, "```json")
-		content = strings.TrimPrefix(content, "```")
-		content = strings.TrimSuffix(content, "```")
-		content = strings.TrimSpace(content)
-
-		// Try to parse as generic JSON first
-		var jsonContent interface{}
-		if err := json.Unmarshal([]byte(content), &jsonContent); err != nil {
-			logger.Log.Warnf("Invalid JSON response: %v", err)
-			lastErr = fmt.Errorf("invalid JSON response: %w", err)
-			baseTemperature -= 0.02
-			continue
+		used := tokens{prompt: int(resp.Usage.InputTokens), completion: int(resp.Usage.OutputTokens)}
+		for _, block := range resp.Content {
+			if block.Type == anthropic.ContentBlockTypeToolUse && block.Name == format.name {
+				return string(block.Input), used, nil
+			}
 		}
-
-		return content, nil
-	}
-
-	return "", fmt.Errorf("failed after %d attempts: %w", maxRetries, lastErr)
+		return "", used, fmt.Errorf("no %s tool call in response from Claude", format.name)
+	})
 }
 
 func (c *Claude) AnalyzeCode(ctx context.Context, question string, files []github.GitHubFile) (answer string, confidence float64, err error) {
-	systemPrompt := `You are a specialized AI code assistant with expertise in analyzing codebases and providing technical explanations.
-
-Your core responsibilities:
-1. Analyze code thoroughly and provide accurate, well-structured explanations
-2. Focus on practical, implementation-focused responses
-3. Always include relevant code examples and file references
-4. Maintain a professional and educational tone
-5. Ensure responses are complete and well-organized
-6. MUST return valid JSON with properly escaped strings (\\n for newlines, \" for quotes)
-
-When analyzing code:
-- Start with a high-level overview
-- Break down complex concepts into clear sections
-- Provide concrete examples for each explanation
-- Reference specific files and code sections
-- Include practical use cases and best practices
-
-Your responses should be:
-- Technical yet accessible
-- Well-structured with clear sections
-- Supported by code examples
-- Focused on practical implementation
-- Complete and self-contained`
-
-	userPrompt := fmt.Sprintf("Analyze the codebase and provide a response in the following JSON format (DO NOT wrap the response in code blocks):\n"+
-		"{\n"+
-		"  \"answer\": \"Your detailed explanation here. Structure your answer as follows:\\n\\n"+
-		"1. Start with a brief overview (2-3 sentences)\\n"+
-		"2. Break down the explanation into clear sections using markdown headers (###)\\n"+
-		"3. For each section:\\n"+
-		"   - Provide a clear explanation\\n"+
-		"   - Include relevant code examples\\n"+
-		"   - Explain when and how to use the feature\\n"+
-		"4. Add relevant code references\\n"+
-		"5. Include practical examples and use cases\\n\\n"+
-		"Use proper markdown formatting for better readability.\",\n"+
-		"  \"confidence\": 0.8,\n"+
-		"  \"relevant_files\": [\"path/to/file.ext\"]\n"+
-		"}\n"+
-		"\n"+
-		"Response Requirements:\n"+
-		"1. Make explanations comprehensive yet concise\n"+
-		"2. Use markdown headers (###) to organize content\n"+
-		"3. Include code examples with proper markdown code blocks\n"+
-		"4. Reference specific files and line numbers when relevant\n"+
-		"5. Provide practical usage examples\n"+
-		"6. Ensure the response is complete (no truncated sentences or examples)\n"+
-		"7. Return ONLY the JSON response, do not wrap it in markdown code blocks\n"+
-		"8. MUST escape all newlines with \\n and quotes with \\\n"+
-		"\n"+
-		"Available Files:\n"+
-		"%s\n"+
-		"\n"+
-		"Question:\n"+
-		"%s", formatFilesForPrompt(files), question)
-
-	logger.Log.Infof("Analyzing code: [%s] with %d files", question, len(files))
-
-	content, err := c.makeRequest(ctx, systemPrompt, userPrompt)
+	p, err := c.prompts.codePrompt(question, files)
 	if err != nil {
 		return "", 0, err
 	}
 
-	var aiResp AIResponse
-	if err := json.Unmarshal([]byte(content), &aiResp); err != nil {
-		return "", 0, fmt.Errorf("failed to parse AI response: %w", err)
+	logger.FromContext(ctx).Infof("Analyzing code with %d files", len(files))
+	logger.FromContext(ctx).Debugf("Analyzing code question: [%s]", question)
+
+	content, err := c.makeRequest(ctx, TaskCode, codeSchema, p)
+	if err != nil {
+		return "", 0, err
 	}
 
-	return aiResp.Answer, aiResp.Confidence, nil
+	return parseCodeAnalysis(content)
 }
 
 func (c *Claude) AnalyzeLabels(ctx context.Context, title, body string, availableLabels string) (github.LabelAnalysis, error) {
-	systemPrompt := `You are an AI assistant specialized in analyzing GitHub issues and suggesting appropriate labels.
-
-Your task is to:
-1. Analyze the issue title and body
-2. Consider the available labels and their descriptions
-3. Suggest relevant labels with confidence scores
-4. Provide brief explanations for your suggestions
-
-Guidelines:
-- Only suggest labels that are highly relevant
-- Consider both technical and non-technical aspects
-- Be conservative with confidence scores
-- Focus on the main topics and themes of the issue`
-
-	userPrompt := fmt.Sprintf("Analyze the issue and suggest appropriate labels. Provide your response in the following JSON format (DO NOT wrap in code blocks):\n"+
-		"{\n"+
-		"  \"suggestedLabels\": {\n"+
-		"    \"label-name\": 0.95,\n"+
-		"    \"another-label\": 0.85\n"+
-		"  },\n"+
-		"  \"explanation\": \"Brief explanation of why these labels were chosen\"\n"+
-		"}\n\n"+
-		"Confidence Score Guide:\n"+
-		"- 0.0-0.3: Weak relevance\n"+
-		"- 0.4-0.6: Moderate relevance\n"+
-		"- 0.7-0.9: Strong relevance\n"+
-		"- 1.0: Perfect match\n\n"+
-		"Issue Title: %s\nIssue Body:\n%s\n\nAvailable Labels:\n%s",
-		title, body, availableLabels)
-
-	logger.Log.Infof("Analyzing labels for issue: [%s]", title)
-
-	content, err := c.makeRequest(ctx, systemPrompt, userPrompt)
+	p, err := c.prompts.labelPrompt(title, body, availableLabels)
 	if err != nil {
 		return github.LabelAnalysis{}, err
 	}
 
-	var analysis github.LabelAnalysis
-	if err := json.Unmarshal([]byte(content), &analysis); err != nil {
-		return github.LabelAnalysis{}, fmt.Errorf("failed to parse label analysis: %w", err)
+	logger.FromContext(ctx).Infof("Analyzing labels for issue: [%s]", title)
+
+	content, err := c.makeRequest(ctx, TaskLabels, labelSchema, p)
+	if err != nil {
+		return github.LabelAnalysis{}, err
+	}
+
+	return parseLabelAnalysis(content)
+}
+
+func (c *Claude) AnalyzeDuplicates(ctx context.Context, title, body string, candidates []github.DuplicateCandidate) (github.DuplicateAnalysis, error) {
+	p, err := c.prompts.duplicatePrompt(title, body, candidates)
+	if err != nil {
+		return github.DuplicateAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Analyzing duplicates for issue: [%s] with %d candidates", title, len(candidates))
+
+	content, err := c.makeRequest(ctx, TaskDuplicates, duplicateSchema, p)
+	if err != nil {
+		return github.DuplicateAnalysis{}, err
+	}
+
+	return parseDuplicateAnalysis(content, candidates)
+}
+
+func (c *Claude) AnalyzeTriage(ctx context.Context, title, body string, components []string) (github.TriageAnalysis, error) {
+	p, err := c.prompts.triagePrompt(title, body, components)
+	if err != nil {
+		return github.TriageAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Triaging issue: [%s] with %d components", title, len(components))
+
+	content, err := c.makeRequest(ctx, TaskTriage, triageSchema(components), p)
+	if err != nil {
+		return github.TriageAnalysis{}, err
+	}
+
+	return parseTriageAnalysis(content, components)
+}
+
+func (c *Claude) AnalyzeRelevantFiles(ctx context.Context, title, body string, paths []string) ([]string, error) {
+	p, err := c.prompts.relevancePrompt(title, body, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Selecting relevant files for issue: [%s] from %d files", title, len(paths))
+
+	content, err := c.makeRequest(ctx, TaskRelevance, relevanceSchema, p)
+	if err != nil {
+		return nil, err
 	}
 
-	return analysis, nil
+	return parseRelevantFiles(content, paths)
 }
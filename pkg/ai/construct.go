@@ -2,6 +2,8 @@ package ai
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/workflowkit/issue-assistant/pkg/github"
@@ -18,46 +20,98 @@ type LabelAnalyzer interface {
 	AnalyzeLabels(ctx context.Context, title, body string, availableLabels string) (labelAnalysis github.LabelAnalysis, err error)
 }
 
+// DuplicateAnalyzer confirms which candidate issues duplicate a GitHub issue
+type DuplicateAnalyzer interface {
+	AnalyzeDuplicates(ctx context.Context, title, body string, candidates []github.DuplicateCandidate) (duplicateAnalysis github.DuplicateAnalysis, err error)
+}
+
+// TriageAnalyzer classifies GitHub issues by type, severity, priority and component
+type TriageAnalyzer interface {
+	AnalyzeTriage(ctx context.Context, title, body string, components []string) (triageAnalysis github.TriageAnalysis, err error)
+}
+
+// RelevanceAnalyzer selects the repository files most relevant to a GitHub issue
+type RelevanceAnalyzer interface {
+	AnalyzeRelevantFiles(ctx context.Context, title, body string, paths []string) (relevantFiles []string, err error)
+}
+
 // AIService combines all analysis capabilities
 type AIService interface {
 	CodeAnalyzer
 	LabelAnalyzer
+	DuplicateAnalyzer
+	TriageAnalyzer
+	RelevanceAnalyzer
 }
 
-// We do not control AI model type because of every AI service has its own model
-// And every single month they are updating their models :)
+// Every provider has a default model, but providers update their models every single month :)
+// so the model, max tokens and temperature can be overridden per provider and per task with
+// WithModelConfig and WithTaskModelConfig.
+
+// ErrUnsupportedProvider is returned for an AI type that is not supported
+var ErrUnsupportedProvider = errors.New("unsupported AI provider")
+
+// NewAIService creates the AI service of a provider
+func NewAIService(aiType AIType, apiKey string, opts ...Option) (AIService, error) {
+	cfg := newConfig(opts)
 
-func NewAIService(aiType AIType, apiKey string) AIService {
 	switch aiType {
 	case AITypeOpenAI:
 		logger.Log.Info("Using OpenAI")
-		return newOpenAIService(apiKey)
+		return newOpenAIService(apiKey, cfg), nil
+	case AITypeAzureOpenAI:
+		logger.Log.Info("Using Azure OpenAI")
+		return newAzureOpenAIService(apiKey, cfg), nil
 	case AITypeClaude:
 		logger.Log.Info("Using Claude")
-		return newClaudeService(apiKey)
+		return newClaudeService(apiKey, cfg), nil
+	case AITypeGemini:
+		logger.Log.Info("Using Gemini")
+		return newGeminiService(apiKey, cfg), nil
+	case AITypeOllama:
+		logger.Log.Info("Using Ollama")
+		return newOllamaService(apiKey, cfg), nil
+	case AITypeLlamaCpp:
+		logger.Log.Info("Using llama.cpp")
+		return newLlamaCppService(apiKey, cfg), nil
 	default:
-		logger.Log.Fatalf("AI type %s is not supported", aiType)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProvider, aiType)
 	}
-
-	return nil
 }
 
 type AIType string
 
 const (
-	AITypeOpenAI AIType = "openai"
-	AITypeClaude AIType = "claude"
+	AITypeOpenAI      AIType = "openai"
+	AITypeAzureOpenAI AIType = "azure"
+	AITypeClaude      AIType = "claude"
+	AITypeGemini      AIType = "gemini"
+	AITypeOllama      AIType = "ollama"
+	AITypeLlamaCpp    AIType = "llamacpp"
 )
 
-func ToAIType(s string) AIType {
+// RequiresAPIKey reports whether the provider needs an API key.
+// Local model servers usually run without authentication.
+func (t AIType) RequiresAPIKey() bool {
+	return t != AITypeOllama && t != AITypeLlamaCpp
+}
+
+// ToAIType parses an AI type, accepting common aliases
+func ToAIType(s string) (AIType, error) {
 	switch strings.ToLower(s) {
 	case "openai":
-		return AITypeOpenAI
+		return AITypeOpenAI, nil
+	case "azure", "azure-openai":
+		return AITypeAzureOpenAI, nil
 	case "claude":
-		return AITypeClaude
+		return AITypeClaude, nil
+	case "gemini":
+		return AITypeGemini, nil
+	case "ollama":
+		return AITypeOllama, nil
+	case "llamacpp", "llama.cpp":
+		return AITypeLlamaCpp, nil
 	default:
-		logger.Log.Fatalf("AI type %s is not supported", s)
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedProvider, s)
 	}
-
-	return ""
 }
@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/workflowkit/issue-assistant/pkg/github"
+)
+
+// maxCandidateBodyLength limits how much of each candidate body is sent to the model
+const maxCandidateBodyLength = 1500
+
+// duplicateSchema is the structured output schema of duplicate analysis
+var duplicateSchema = responseSchema{name: "duplicate_analysis", schema: schemaFor(github.DuplicateAnalysis{})}
+
+// duplicatePromptData is available to the duplicate analysis prompt template
+type duplicatePromptData struct {
+	Title      string
+	Body       string
+	Candidates string
+}
+
+// duplicatePrompt renders the prompt of duplicate analysis
+func (r *PromptRegistry) duplicatePrompt(title, body string, candidates []github.DuplicateCandidate) (prompt, error) {
+	var formatted strings.Builder
+	for _, candidate := range candidates {
+		candidateBody := truncate(candidate.Issue.Body, maxCandidateBodyLength)
+		formatted.WriteString(fmt.Sprintf("Issue #%d (%s, text similarity %.2f)\nTitle: %s\nBody:\n%s\n\n",
+			candidate.Issue.Number, candidate.Issue.State, candidate.Similarity,
+			untrustedTitle(candidate.Issue.Title), untrusted("issue_body", candidateBody)))
+	}
+
+	return r.render(TaskDuplicates, duplicatePromptData{
+		Title:      untrustedTitle(title),
+		Body:       untrusted("issue_body", body),
+		Candidates: formatted.String(),
+	})
+}
+
+// truncate shortens text to at most maxLength bytes without splitting a UTF-8 character
+func truncate(text string, maxLength int) string {
+	if len(text) <= maxLength {
+		return text
+	}
+	end := maxLength
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end] + "..."
+}
+
+// parseDuplicateAnalysis decodes the model response and drops matches that were not candidates
+func parseDuplicateAnalysis(content string, candidates []github.DuplicateCandidate) (github.DuplicateAnalysis, error) {
+	var analysis github.DuplicateAnalysis
+	if err := json.Unmarshal([]byte(content), &analysis); err != nil {
+		return github.DuplicateAnalysis{}, fmt.Errorf("failed to parse duplicate analysis: %w", err)
+	}
+
+	known := make(map[int]bool, len(candidates))
+	for _, candidate := range candidates {
+		known[candidate.Issue.Number] = true
+	}
+
+	var duplicates []github.DuplicateMatch
+	for _, match := range analysis.Duplicates {
+		if known[match.Number] {
+			duplicates = append(duplicates, match)
+		}
+	}
+	analysis.Duplicates = duplicates
+
+	return analysis, nil
+}
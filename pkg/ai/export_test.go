@@ -0,0 +1,4 @@
+package ai
+
+// ErrInvalidOutput exposes errInvalidOutput to the tests of package ai_test, which can use aitest
+var ErrInvalidOutput = errInvalidOutput
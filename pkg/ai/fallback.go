@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/workflowkit/issue-assistant/pkg/github"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// Provider is a named AI service in a fallback chain
+type Provider struct {
+	Name    string
+	Service AIService
+}
+
+// Fallback tries its providers in order until one of them answers
+type Fallback struct {
+	providers []Provider
+}
+
+// NewFallbackService creates an AI service that falls over to the next provider
+// when a provider fails, e.g. on transport errors, rate limits or invalid output
+func NewFallbackService(providers ...Provider) AIService {
+	return &Fallback{providers: providers}
+}
+
+// try calls fn with every provider in order until one succeeds and records the provider that answered
+func try[T any](ctx context.Context, f *Fallback, fn func(AIService) (T, error)) (T, error) {
+	var zero T
+	var errs providerErrors
+
+	for i, provider := range f.providers {
+		result, err := fn(provider.Service)
+		if err == nil {
+			logger.FromContext(ctx).Infof("AI request answered by %s", provider.Name)
+			MetadataFromContext(ctx).setProvider(provider.Name)
+			return result, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name, err))
+
+		// Cancellation applies to every provider, there is no point in falling over
+		if ctx.Err() != nil {
+			return zero, errors.Join(err, ctx.Err())
+		}
+		if !canFallOver(err) {
+			return zero, fmt.Errorf("%s: %w", provider.Name, err)
+		}
+
+		if i+1 < len(f.providers) {
+			logger.FromContext(ctx).Warnf("%s failed, falling over to %s: %v", provider.Name, f.providers[i+1].Name, err)
+		}
+	}
+
+	return zero, fmt.Errorf("all providers failed: %w", errs)
+}
+
+// canFallOver reports whether another provider may succeed where one failed: on transport errors,
+// rate limits, server errors and invalid output. Rejected keys, invalid requests, prompts that
+// fail to render and an exhausted budget fail the same way with every provider.
+func canFallOver(err error) bool {
+	var requestErr *RequestError
+	if !errors.As(err, &requestErr) {
+		return false
+	}
+	return requestErr.Retryable || errors.Is(err, errInvalidOutput)
+}
+
+// providerErrors are the errors of all providers of a fallback chain, matchable with errors.Is and errors.As
+type providerErrors []error
+
+func (e providerErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e providerErrors) Unwrap() []error {
+	return e
+}
+
+func (f *Fallback) AnalyzeCode(ctx context.Context, question string, files []github.GitHubFile) (string, float64, error) {
+	type codeAnalysis struct {
+		answer     string
+		confidence float64
+	}
+
+	result, err := try(ctx, f, func(service AIService) (codeAnalysis, error) {
+		answer, confidence, err := service.AnalyzeCode(ctx, question, files)
+		return codeAnalysis{answer, confidence}, err
+	})
+	return result.answer, result.confidence, err
+}
+
+func (f *Fallback) AnalyzeLabels(ctx context.Context, title, body string, availableLabels string) (github.LabelAnalysis, error) {
+	return try(ctx, f, func(service AIService) (github.LabelAnalysis, error) {
+		return service.AnalyzeLabels(ctx, title, body, availableLabels)
+	})
+}
+
+func (f *Fallback) AnalyzeDuplicates(ctx context.Context, title, body string, candidates []github.DuplicateCandidate) (github.DuplicateAnalysis, error) {
+	return try(ctx, f, func(service AIService) (github.DuplicateAnalysis, error) {
+		return service.AnalyzeDuplicates(ctx, title, body, candidates)
+	})
+}
+
+func (f *Fallback) AnalyzeTriage(ctx context.Context, title, body string, components []string) (github.TriageAnalysis, error) {
+	return try(ctx, f, func(service AIService) (github.TriageAnalysis, error) {
+		return service.AnalyzeTriage(ctx, title, body, components)
+	})
+}
+
+func (f *Fallback) AnalyzeRelevantFiles(ctx context.Context, title, body string, paths []string) ([]string, error) {
+	return try(ctx, f, func(service AIService) ([]string, error) {
+		return service.AnalyzeRelevantFiles(ctx, title, body, paths)
+	})
+}
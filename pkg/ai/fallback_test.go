@@ -0,0 +1,144 @@
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/workflowkit/issue-assistant/pkg/ai"
+	"github.com/workflowkit/issue-assistant/pkg/ai/aitest"
+	"github.com/workflowkit/issue-assistant/pkg/github"
+)
+
+var answer = github.LabelAnalysis{SuggestedLabels: map[string]float64{"bug": 0.9}}
+
+// chain returns a fallback chain of a primary and a secondary mock
+func chain(primary, secondary *aitest.Mock) ai.AIService {
+	return ai.NewFallbackService(
+		ai.Provider{Name: "primary", Service: primary},
+		ai.Provider{Name: "secondary", Service: secondary})
+}
+
+func TestFallbackFallsOver(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		fallOver bool
+	}{
+		{"server error", &ai.RequestError{Provider: "primary", StatusCode: http.StatusServiceUnavailable, Attempts: 3, Retryable: true, Err: errors.New("overloaded")}, true},
+		{"rate limit", &ai.RequestError{Provider: "primary", StatusCode: http.StatusTooManyRequests, Attempts: 3, Retryable: true, Err: errors.New("slow down")}, true},
+		{"transport error", &ai.RequestError{Provider: "primary", Attempts: 3, Retryable: true, Err: errors.New("connection reset")}, true},
+		{"invalid output", &ai.RequestError{Provider: "primary", StatusCode: http.StatusOK, Attempts: 2, Err: fmt.Errorf("%w: missing labels", ai.ErrInvalidOutput)}, true},
+		{"wrapped request error", fmt.Errorf("labels: %w", &ai.RequestError{Provider: "primary", Retryable: true, Err: errors.New("timeout")}), true},
+		{"unauthorized", &ai.RequestError{Provider: "primary", StatusCode: http.StatusUnauthorized, Attempts: 1, Err: errors.New("invalid api key")}, false},
+		{"forbidden", &ai.RequestError{Provider: "primary", StatusCode: http.StatusForbidden, Attempts: 1, Err: errors.New("no access")}, false},
+		{"bad request", &ai.RequestError{Provider: "primary", StatusCode: http.StatusBadRequest, Attempts: 1, Err: errors.New("context too long")}, false},
+		{"budget", fmt.Errorf("%w: $1.00 spent", ai.ErrBudgetExceeded), false},
+		{"prompt", errors.New("failed to render prompt"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &aitest.Mock{Labels: []aitest.Response[github.LabelAnalysis]{{Err: tt.err}}}
+			secondary := &aitest.Mock{Labels: []aitest.Response[github.LabelAnalysis]{{Value: answer}}}
+			ctx, md := ai.WithMetadata(context.Background(), "labels")
+
+			got, err := chain(primary, secondary).AnalyzeLabels(ctx, "Widgets vanish", "", "bug")
+
+			if fellOver := len(secondary.Calls()) > 0; fellOver != tt.fallOver {
+				t.Fatalf("fell over to secondary = %t, want %t (error %v)", fellOver, tt.fallOver, err)
+			}
+			if !tt.fallOver {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("AnalyzeLabels() error = %v, want the primary error", err)
+				}
+				if md.Provider() != "" {
+					t.Errorf("provider = %q, want none recorded", md.Provider())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AnalyzeLabels() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, answer) {
+				t.Errorf("AnalyzeLabels() = %+v, want %+v", got, answer)
+			}
+			if md.Provider() != "secondary" {
+				t.Errorf("provider = %q, want secondary", md.Provider())
+			}
+		})
+	}
+}
+
+func TestFallbackKeepsAuthErrors(t *testing.T) {
+	primary := &aitest.Mock{Triage: []aitest.Response[github.TriageAnalysis]{{
+		Err: &ai.RequestError{Provider: "primary", StatusCode: http.StatusUnauthorized, Attempts: 1, Err: errors.New("invalid api key")},
+	}}}
+	secondary := &aitest.Mock{Triage: []aitest.Response[github.TriageAnalysis]{{Value: github.TriageAnalysis{Priority: "p1"}}}}
+
+	_, err := chain(primary, secondary).AnalyzeTriage(context.Background(), "Widgets vanish", "", nil)
+
+	if !errors.Is(err, ai.ErrAuthentication) {
+		t.Errorf("AnalyzeTriage() error = %v, want ErrAuthentication", err)
+	}
+	if calls := secondary.Calls(); len(calls) != 0 {
+		t.Errorf("secondary was called after an auth error: %+v", calls)
+	}
+}
+
+func TestFallbackMetadata(t *testing.T) {
+	retryable := &ai.RequestError{Provider: "primary", StatusCode: http.StatusBadGateway, Attempts: 3, Retryable: true, Err: errors.New("bad gateway")}
+	primary := &aitest.Mock{Labels: []aitest.Response[github.LabelAnalysis]{{Value: answer}, {Err: retryable}, {Err: retryable}}}
+	secondary := &aitest.Mock{Labels: []aitest.Response[github.LabelAnalysis]{{Value: answer}, {Err: retryable}}}
+	service := chain(primary, secondary)
+	ctx, md := ai.WithMetadata(context.Background(), "labels")
+
+	// Each answered request records the provider that answered it
+	if _, err := service.AnalyzeLabels(ctx, "Widgets vanish", "", "bug"); err != nil {
+		t.Fatalf("first AnalyzeLabels() error = %v", err)
+	}
+	if md.Provider() != "primary" {
+		t.Errorf("provider after the first request = %q, want primary", md.Provider())
+	}
+	if _, err := service.AnalyzeLabels(ctx, "Widgets vanish", "", "bug"); err != nil {
+		t.Fatalf("second AnalyzeLabels() error = %v", err)
+	}
+	if md.Provider() != "secondary" {
+		t.Errorf("provider after the second request = %q, want secondary", md.Provider())
+	}
+
+	// A failed request keeps the provider of the last answer and reports the errors of every provider
+	_, err := service.AnalyzeLabels(ctx, "Widgets vanish", "", "bug")
+	if err == nil {
+		t.Fatal("third AnalyzeLabels() succeeded, want all providers failed")
+	}
+	var requestErr *ai.RequestError
+	if !errors.As(err, &requestErr) || requestErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("third AnalyzeLabels() error = %v, want the request errors", err)
+	}
+	if md.Provider() != "secondary" {
+		t.Errorf("provider after the failed request = %q, want secondary", md.Provider())
+	}
+	if md.Feature() != "labels" {
+		t.Errorf("feature = %q, want labels", md.Feature())
+	}
+}
+
+func TestFallbackCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	primary := &aitest.Mock{Labels: []aitest.Response[github.LabelAnalysis]{{Value: answer}}}
+	secondary := &aitest.Mock{Labels: []aitest.Response[github.LabelAnalysis]{{Value: answer}}}
+
+	_, err := chain(primary, secondary).AnalyzeLabels(ctx, "Widgets vanish", "", "bug")
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("AnalyzeLabels() error = %v, want context.Canceled", err)
+	}
+	if calls := secondary.Calls(); len(calls) != 0 {
+		t.Errorf("secondary was called after cancellation: %+v", calls)
+	}
+}
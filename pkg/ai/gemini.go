@@ -0,0 +1,225 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/workflowkit/issue-assistant/pkg/github"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// Defaults of the Gemini API
+const (
+	defaultGeminiEndpoint = "https://generativelanguage.googleapis.com/v1beta"
+	defaultGeminiModel    = "gemini-2.0-flash"
+)
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiRequest struct {
+	SystemInstruction geminiContent   `json:"systemInstruction"`
+	Contents          []geminiContent `json:"contents"`
+	GenerationConfig  struct {
+		Temperature      float64 `json:"temperature"`
+		MaxOutputTokens  int     `json:"maxOutputTokens"`
+		ResponseMimeType string  `json:"responseMimeType"`
+		ResponseSchema   *Schema `json:"responseSchema"`
+	} `json:"generationConfig"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+type Gemini struct {
+	client   *http.Client
+	engine   requestEngine
+	endpoint string
+	models   modelSelector
+	prompts  *PromptRegistry
+	apiKey   string
+}
+
+func newGeminiService(apiKey string, cfg config) AIService {
+	endpoint := defaultGeminiEndpoint
+	if cfg.endpoint != "" {
+		endpoint = cfg.endpoint
+	}
+	return &Gemini{
+		client:   newHTTPClient(cfg),
+		engine:   newRequestEngine("Gemini", cfg),
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		models:   newModelSelector(cfg, defaultGeminiModel),
+		prompts:  cfg.prompts,
+		apiKey:   apiKey,
+	}
+}
+
+// makeRequest sends a generateContent request through the shared request engine
+func (g *Gemini) makeRequest(ctx context.Context, task Task, format responseSchema, p prompt) (string, error) {
+	model, maxTokens, temperature := g.models.resolve(task)
+	logger.FromContext(ctx).Debugf("using Gemini model: %s temperature: %.2f", model, temperature)
+
+	return g.engine.do(ctx, request{model: model, maxTokens: maxTokens, prompt: p, schema: format.schema}, func(ctx context.Context) (string, tokens, error) {
+		return g.generateContent(ctx, model, format, p.system, p.user, maxTokens, temperature)
+	})
+}
+
+// generateContent calls the Gemini generateContent API with the response schema
+func (g *Gemini) generateContent(ctx context.Context, model string, format responseSchema, systemPrompt, userPrompt string, maxTokens int, temperature float64) (string, tokens, error) {
+	var request geminiRequest
+	request.SystemInstruction = geminiContent{Parts: []geminiPart{{Text: systemPrompt}}}
+	request.Contents = []geminiContent{{Role: "user", Parts: []geminiPart{{Text: userPrompt}}}}
+	request.GenerationConfig.Temperature = temperature
+	request.GenerationConfig.MaxOutputTokens = maxTokens
+	request.GenerationConfig.ResponseMimeType = "application/json"
+	// Gemini schemas are an OpenAPI subset without additionalProperties
+	request.GenerationConfig.ResponseSchema = format.schema.without(true, false)
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", tokens{}, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent", g.endpoint, url.PathEscape(model))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", tokens{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", g.apiKey)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", tokens{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", tokens{}, fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var response geminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", tokens{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	used := tokens{prompt: response.UsageMetadata.PromptTokenCount, completion: response.UsageMetadata.CandidatesTokenCount}
+	if response.PromptFeedback.BlockReason != "" {
+		return "", used, permanent(fmt.Errorf("prompt blocked: %s", response.PromptFeedback.BlockReason))
+	}
+	if len(response.Candidates) == 0 {
+		return "", used, fmt.Errorf("empty response from Gemini")
+	}
+
+	var content strings.Builder
+	for _, part := range response.Candidates[0].Content.Parts {
+		content.WriteString(part.Text)
+	}
+	return content.String(), used, nil
+}
+
+func (g *Gemini) AnalyzeCode(ctx context.Context, question string, files []github.GitHubFile) (answer string, confidence float64, err error) {
+	p, err := g.prompts.codePrompt(question, files)
+	if err != nil {
+		return "", 0, err
+	}
+
+	logger.FromContext(ctx).Infof("Analyzing code with %d files", len(files))
+	logger.FromContext(ctx).Debugf("Analyzing code question: [%s]", question)
+
+	content, err := g.makeRequest(ctx, TaskCode, codeSchema, p)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return parseCodeAnalysis(content)
+}
+
+func (g *Gemini) AnalyzeLabels(ctx context.Context, title, body string, availableLabels string) (github.LabelAnalysis, error) {
+	p, err := g.prompts.labelPrompt(title, body, availableLabels)
+	if err != nil {
+		return github.LabelAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Analyzing labels for issue: [%s]", title)
+
+	content, err := g.makeRequest(ctx, TaskLabels, labelSchema, p)
+	if err != nil {
+		return github.LabelAnalysis{}, err
+	}
+
+	return parseLabelAnalysis(content)
+}
+
+func (g *Gemini) AnalyzeDuplicates(ctx context.Context, title, body string, candidates []github.DuplicateCandidate) (github.DuplicateAnalysis, error) {
+	p, err := g.prompts.duplicatePrompt(title, body, candidates)
+	if err != nil {
+		return github.DuplicateAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Analyzing duplicates for issue: [%s] with %d candidates", title, len(candidates))
+
+	content, err := g.makeRequest(ctx, TaskDuplicates, duplicateSchema, p)
+	if err != nil {
+		return github.DuplicateAnalysis{}, err
+	}
+
+	return parseDuplicateAnalysis(content, candidates)
+}
+
+func (g *Gemini) AnalyzeTriage(ctx context.Context, title, body string, components []string) (github.TriageAnalysis, error) {
+	p, err := g.prompts.triagePrompt(title, body, components)
+	if err != nil {
+		return github.TriageAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Triaging issue: [%s] with %d components", title, len(components))
+
+	content, err := g.makeRequest(ctx, TaskTriage, triageSchema(components), p)
+	if err != nil {
+		return github.TriageAnalysis{}, err
+	}
+
+	return parseTriageAnalysis(content, components)
+}
+
+func (g *Gemini) AnalyzeRelevantFiles(ctx context.Context, title, body string, paths []string) ([]string, error) {
+	p, err := g.prompts.relevancePrompt(title, body, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Selecting relevant files for issue: [%s] from %d files", title, len(paths))
+
+	content, err := g.makeRequest(ctx, TaskRelevance, relevanceSchema, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRelevantFiles(content, paths)
+}
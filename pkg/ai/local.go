@@ -0,0 +1,266 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/workflowkit/issue-assistant/pkg/github"
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// Defaults of the local model servers
+const (
+	defaultOllamaEndpoint   = "http://localhost:11434"
+	defaultOllamaModel      = "llama3.1"
+	defaultLlamaCppEndpoint = "http://localhost:8080"
+)
+
+// localBackend is the HTTP API flavor of a local model server
+type localBackend string
+
+const (
+	backendOllama   localBackend = "Ollama"
+	backendLlamaCpp localBackend = "llama.cpp"
+)
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Local talks to a self-hosted model server, either Ollama or a llama.cpp server
+type Local struct {
+	client   *http.Client
+	engine   requestEngine
+	backend  localBackend
+	endpoint string
+	models   modelSelector
+	prompts  *PromptRegistry
+	apiKey   string
+}
+
+func newOllamaService(apiKey string, cfg config) AIService {
+	return newLocalService(backendOllama, apiKey, cfg, defaultOllamaEndpoint, defaultOllamaModel)
+}
+
+func newLlamaCppService(apiKey string, cfg config) AIService {
+	// llama.cpp serves the single model it was started with, the model name is informational
+	return newLocalService(backendLlamaCpp, apiKey, cfg, defaultLlamaCppEndpoint, "")
+}
+
+func newLocalService(backend localBackend, apiKey string, cfg config, endpoint, model string) *Local {
+	if cfg.endpoint != "" {
+		endpoint = cfg.endpoint
+	}
+	return &Local{
+		client:   newHTTPClient(cfg),
+		engine:   newRequestEngine(string(backend), cfg),
+		backend:  backend,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		models:   newModelSelector(cfg, model),
+		prompts:  cfg.prompts,
+		apiKey:   apiKey,
+	}
+}
+
+// makeRequest sends a chat request to the model server through the shared request engine
+func (l *Local) makeRequest(ctx context.Context, task Task, format responseSchema, p prompt) (string, error) {
+	model, maxTokens, temperature := l.models.resolve(task)
+	logger.FromContext(ctx).Debugf("using %s model: %s temperature: %.2f", l.backend, model, temperature)
+
+	messages := []chatMessage{
+		{Role: "system", Content: p.system},
+		{Role: "user", Content: p.user},
+	}
+
+	return l.engine.do(ctx, request{model: model, maxTokens: maxTokens, prompt: p, schema: format.schema}, func(ctx context.Context) (string, tokens, error) {
+		if l.backend == backendOllama {
+			return l.ollamaChat(ctx, model, messages, format, maxTokens, temperature)
+		}
+		return l.llamaCppChat(ctx, model, messages, format, maxTokens, temperature)
+	})
+}
+
+// ollamaChat calls the Ollama chat API with the response schema as output format
+func (l *Local) ollamaChat(ctx context.Context, model string, messages []chatMessage, format responseSchema, maxTokens int, temperature float64) (string, tokens, error) {
+	request := map[string]interface{}{
+		"model":    model,
+		"messages": messages,
+		"stream":   false,
+		"format":   format.schema,
+		"options": map[string]interface{}{
+			"temperature": temperature,
+			"num_predict": maxTokens,
+		},
+	}
+
+	var response struct {
+		Message         chatMessage `json:"message"`
+		PromptEvalCount int         `json:"prompt_eval_count"`
+		EvalCount       int         `json:"eval_count"`
+	}
+	if err := l.post(ctx, "/api/chat", request, &response); err != nil {
+		return "", tokens{}, err
+	}
+
+	return response.Message.Content, tokens{prompt: response.PromptEvalCount, completion: response.EvalCount}, nil
+}
+
+// llamaCppChat calls the OpenAI compatible chat completions API of a llama.cpp server,
+// which turns the response schema into a grammar that constrains sampling
+func (l *Local) llamaCppChat(ctx context.Context, model string, messages []chatMessage, format responseSchema, maxTokens int, temperature float64) (string, tokens, error) {
+	request := map[string]interface{}{
+		"messages":    messages,
+		"temperature": temperature,
+		"max_tokens":  maxTokens,
+		"response_format": map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   format.name,
+				"schema": format.schema,
+			},
+		},
+	}
+	if model != "" {
+		request["model"] = model
+	}
+
+	var response struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := l.post(ctx, "/v1/chat/completions", request, &response); err != nil {
+		return "", tokens{}, err
+	}
+
+	used := tokens{prompt: response.Usage.PromptTokens, completion: response.Usage.CompletionTokens}
+	if len(response.Choices) == 0 {
+		return "", used, fmt.Errorf("empty response from %s", l.backend)
+	}
+
+	return response.Choices[0].Message.Content, used, nil
+}
+
+// post sends a JSON request to the model server and decodes the JSON response
+func (l *Local) post(ctx context.Context, path string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if l.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+l.apiKey)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) AnalyzeCode(ctx context.Context, question string, files []github.GitHubFile) (answer string, confidence float64, err error) {
+	p, err := l.prompts.codePrompt(question, files)
+	if err != nil {
+		return "", 0, err
+	}
+
+	logger.FromContext(ctx).Infof("Analyzing code with %d files", len(files))
+	logger.FromContext(ctx).Debugf("Analyzing code question: [%s]", question)
+
+	content, err := l.makeRequest(ctx, TaskCode, codeSchema, p)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return parseCodeAnalysis(content)
+}
+
+func (l *Local) AnalyzeLabels(ctx context.Context, title, body string, availableLabels string) (github.LabelAnalysis, error) {
+	p, err := l.prompts.labelPrompt(title, body, availableLabels)
+	if err != nil {
+		return github.LabelAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Analyzing labels for issue: [%s]", title)
+
+	content, err := l.makeRequest(ctx, TaskLabels, labelSchema, p)
+	if err != nil {
+		return github.LabelAnalysis{}, err
+	}
+
+	return parseLabelAnalysis(content)
+}
+
+func (l *Local) AnalyzeDuplicates(ctx context.Context, title, body string, candidates []github.DuplicateCandidate) (github.DuplicateAnalysis, error) {
+	p, err := l.prompts.duplicatePrompt(title, body, candidates)
+	if err != nil {
+		return github.DuplicateAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Analyzing duplicates for issue: [%s] with %d candidates", title, len(candidates))
+
+	content, err := l.makeRequest(ctx, TaskDuplicates, duplicateSchema, p)
+	if err != nil {
+		return github.DuplicateAnalysis{}, err
+	}
+
+	return parseDuplicateAnalysis(content, candidates)
+}
+
+func (l *Local) AnalyzeTriage(ctx context.Context, title, body string, components []string) (github.TriageAnalysis, error) {
+	p, err := l.prompts.triagePrompt(title, body, components)
+	if err != nil {
+		return github.TriageAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Triaging issue: [%s] with %d components", title, len(components))
+
+	content, err := l.makeRequest(ctx, TaskTriage, triageSchema(components), p)
+	if err != nil {
+		return github.TriageAnalysis{}, err
+	}
+
+	return parseTriageAnalysis(content, components)
+}
+
+func (l *Local) AnalyzeRelevantFiles(ctx context.Context, title, body string, paths []string) ([]string, error) {
+	p, err := l.prompts.relevancePrompt(title, body, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Selecting relevant files for issue: [%s] from %d files", title, len(paths))
+
+	content, err := l.makeRequest(ctx, TaskRelevance, relevanceSchema, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRelevantFiles(content, paths)
+}
@@ -0,0 +1,96 @@
+package ai
+
+import (
+	"context"
+	"sync"
+)
+
+type metadataKey struct{}
+
+// Metadata collects information about the AI requests made with a context,
+// such as the provider that answered them, the prompt version they used and their token usage
+type Metadata struct {
+	mu       sync.Mutex
+	feature  string
+	provider string
+	prompt   string
+	usage    Usage
+}
+
+// WithMetadata returns a context that records metadata of the AI requests made with it
+// on behalf of a feature
+func WithMetadata(ctx context.Context, feature string) (context.Context, *Metadata) {
+	md := &Metadata{feature: feature}
+	return context.WithValue(ctx, metadataKey{}, md), md
+}
+
+// MetadataFromContext returns the metadata recorded for the context, or nil when none is recorded
+func MetadataFromContext(ctx context.Context) *Metadata {
+	md, _ := ctx.Value(metadataKey{}).(*Metadata)
+	return md
+}
+
+// Provider returns the name of the provider that answered the last request
+func (m *Metadata) Provider() string {
+	if m == nil {
+		return ""
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.provider
+}
+
+func (m *Metadata) setProvider(provider string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.provider = provider
+}
+
+// Prompt returns the prompt version of the last answered request, e.g. labels@1
+func (m *Metadata) Prompt() string {
+	if m == nil {
+		return ""
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.prompt
+}
+
+func (m *Metadata) setPrompt(version string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prompt = version
+}
+
+// Feature returns the feature the requests are made for
+func (m *Metadata) Feature() string {
+	if m == nil {
+		return ""
+	}
+	return m.feature
+}
+
+// Usage returns the token usage of the requests, including failed attempts
+func (m *Metadata) Usage() Usage {
+	if m == nil {
+		return Usage{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage
+}
+
+func (m *Metadata) addUsage(usage Usage) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage = m.usage.add(usage)
+}
@@ -0,0 +1,159 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Task identifies the analysis a request is made for, so each can use its own model
+type Task string
+
+const (
+	TaskCode       Task = "code"       // Code analysis comments
+	TaskLabels     Task = "labels"     // Label suggestions
+	TaskDuplicates Task = "duplicates" // Duplicate confirmation
+	TaskTriage     Task = "triage"     // Structured triage
+	TaskRelevance  Task = "relevance"  // Relevant file selection
+)
+
+// Tasks lists all tasks
+var Tasks = []Task{TaskCode, TaskLabels, TaskDuplicates, TaskTriage, TaskRelevance}
+
+// Request defaults used when neither the provider nor the configuration set them
+const (
+	defaultMaxTokens   = 2000
+	defaultTemperature = 0.1
+)
+
+// ModelConfig selects the model and sampling parameters of requests. Zero values
+// (empty model, zero max tokens, nil temperature) fall back to the defaults.
+type ModelConfig struct {
+	Model       string
+	MaxTokens   int
+	Temperature *float64
+}
+
+// ParseTask converts a task name to a Task
+func ParseTask(s string) (Task, error) {
+	for _, task := range Tasks {
+		if strings.EqualFold(s, string(task)) {
+			return task, nil
+		}
+	}
+	return "", fmt.Errorf("unknown task %q, expected one of %s", s, joinTasks())
+}
+
+// modelSelector resolves the model configuration of a task
+type modelSelector struct {
+	defaults ModelConfig
+	tasks    map[Task]ModelConfig
+}
+
+func newModelSelector(cfg config, defaultModel string) modelSelector {
+	defaults := ModelConfig{Model: defaultModel, MaxTokens: defaultMaxTokens, Temperature: float64Ptr(defaultTemperature)}
+	return modelSelector{
+		defaults: merge(defaults, cfg.models),
+		tasks:    cfg.taskModels,
+	}
+}
+
+// resolve returns the fully populated model configuration of a task
+func (s modelSelector) resolve(task Task) (string, int, float64) {
+	resolved := merge(s.defaults, s.tasks[task])
+	return resolved.Model, resolved.MaxTokens, *resolved.Temperature
+}
+
+// merge overrides the set fields of base with those of override
+func merge(base, override ModelConfig) ModelConfig {
+	if override.Model != "" {
+		base.Model = override.Model
+	}
+	if override.MaxTokens != 0 {
+		base.MaxTokens = override.MaxTokens
+	}
+	if override.Temperature != nil {
+		base.Temperature = override.Temperature
+	}
+	return base
+}
+
+// Models returns the models the requests of a provider use with the given options
+func Models(aiType AIType, opts ...Option) []string {
+	selector := newModelSelector(newConfig(opts), defaultModel(aiType))
+	var models []string
+	for _, task := range Tasks {
+		model, _, _ := selector.resolve(task)
+		if !slices.Contains(models, model) {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// defaultModel returns the model of a provider when none is configured
+func defaultModel(aiType AIType) string {
+	switch aiType {
+	case AITypeOpenAI, AITypeAzureOpenAI:
+		return openai.GPT4oMini
+	case AITypeClaude:
+		return defaultClaudeModel
+	case AITypeGemini:
+		return defaultGeminiModel
+	case AITypeOllama:
+		return defaultOllamaModel
+	default:
+		// llama.cpp serves the model it was started with
+		return ""
+	}
+}
+
+// Validate checks the model configuration options against the limits of the provider
+func Validate(aiType AIType, opts ...Option) error {
+	cfg := newConfig(opts)
+
+	if err := validateModelConfig(aiType, cfg.models); err != nil {
+		return err
+	}
+	for task, models := range cfg.taskModels {
+		if _, err := ParseTask(string(task)); err != nil {
+			return err
+		}
+		if err := validateModelConfig(aiType, models); err != nil {
+			return fmt.Errorf("task %s: %w", task, err)
+		}
+	}
+	return nil
+}
+
+func validateModelConfig(aiType AIType, models ModelConfig) error {
+	if models.MaxTokens < 0 {
+		return errors.New("max tokens cannot be negative")
+	}
+	if models.Temperature != nil {
+		// Anthropic accepts temperatures up to 1, the other providers up to 2
+		maxTemperature := 2.0
+		if aiType == AITypeClaude {
+			maxTemperature = 1.0
+		}
+		if *models.Temperature < 0 || *models.Temperature > maxTemperature {
+			return fmt.Errorf("temperature must be between 0 and %.0f for %s", maxTemperature, aiType)
+		}
+	}
+	return nil
+}
+
+func joinTasks() string {
+	names := make([]string, 0, len(Tasks))
+	for _, task := range Tasks {
+		names = append(names, string(task))
+	}
+	return strings.Join(names, ", ")
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -2,7 +2,6 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -11,203 +10,180 @@ import (
 	"github.com/workflowkit/issue-assistant/pkg/logger"
 )
 
-type AIResponse struct {
-	Answer        string   `json:"answer"`
-	Confidence    float64  `json:"confidence"`
-	RelevantFiles []string `json:"relevant_files"`
-}
+// defaultAzureAPIVersion is the Azure OpenAI API version used when none is configured
+const defaultAzureAPIVersion = "2024-10-21"
 
 type OpenAI struct {
-	client *openai.Client
+	client  *openai.Client
+	engine  requestEngine
+	models  modelSelector
+	prompts *PromptRegistry
 }
 
-func newOpenAIService(apiKey string) AIService {
-	return &OpenAI{
-		client: openai.NewClient(apiKey),
+func newOpenAIService(apiKey string, cfg config) AIService {
+	clientConfig := openai.DefaultConfig(apiKey)
+	if cfg.endpoint != "" {
+		clientConfig.BaseURL = strings.TrimSuffix(cfg.endpoint, "/")
+	}
+	clientConfig.OrgID = cfg.organization
+
+	return newOpenAIServiceWithConfig("OpenAI", clientConfig, cfg)
+}
+
+func newAzureOpenAIService(apiKey string, cfg config) AIService {
+	clientConfig := openai.DefaultAzureConfig(apiKey, strings.TrimSuffix(cfg.endpoint, "/"))
+	clientConfig.APIVersion = defaultAzureAPIVersion
+	if cfg.apiVersion != "" {
+		clientConfig.APIVersion = cfg.apiVersion
 	}
+	if len(cfg.azureDeployments) > 0 {
+		defaultMapper := clientConfig.AzureModelMapperFunc
+		clientConfig.AzureModelMapperFunc = func(model string) string {
+			if deployment, ok := cfg.azureDeployments[model]; ok {
+				return deployment
+			}
+			if deployment, ok := cfg.azureDeployments[""]; ok {
+				return deployment
+			}
+			return defaultMapper(model)
+		}
+	}
+
+	return newOpenAIServiceWithConfig("Azure OpenAI", clientConfig, cfg)
 }
 
-// makeRequest is a helper function to make OpenAI API requests with retries
-func (a *OpenAI) makeRequest(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
-	const maxRetries = 3
-	var lastErr error
-	baseTemperature := float32(0.1)
+func newOpenAIServiceWithConfig(provider string, clientConfig openai.ClientConfig, cfg config) AIService {
+	clientConfig.HTTPClient = newHTTPClient(cfg)
+	return &OpenAI{
+		client:  openai.NewClientWithConfig(clientConfig),
+		engine:  newRequestEngine(provider, cfg),
+		models:  newModelSelector(cfg, openai.GPT4oMini),
+		prompts: cfg.prompts,
+	}
+}
 
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		logger.Log.Debugf("making OpenAI request: %d with temperature: %.2f", attempt+1, baseTemperature)
+// makeRequest sends a chat completion request with a strict JSON schema response format through the shared request engine
+func (a *OpenAI) makeRequest(ctx context.Context, task Task, format responseSchema, p prompt) (string, error) {
+	model, maxTokens, temperature := a.models.resolve(task)
+	logger.FromContext(ctx).Debugf("using %s model: %s temperature: %.2f", a.engine.provider, model, temperature)
 
+	return a.engine.do(ctx, request{model: model, maxTokens: maxTokens, prompt: p, schema: format.schema}, func(ctx context.Context) (string, tokens, error) {
 		resp, err := a.client.CreateChatCompletion(
 			ctx,
 			openai.ChatCompletionRequest{
-				Model: openai.GPT4oMini,
+				Model: model,
 				Messages: []openai.ChatCompletionMessage{
 					{
 						Role:    openai.ChatMessageRoleSystem,
-						Content: systemPrompt,
+						Content: p.system,
 					},
 					{
 						Role:    openai.ChatMessageRoleUser,
-						Content: userPrompt,
+						Content: p.user,
+					},
+				},
+				Temperature: float32(temperature),
+				MaxTokens:   maxTokens,
+				ResponseFormat: &openai.ChatCompletionResponseFormat{
+					Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+					JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+						Name: format.name,
+						// Strict mode rejects range keywords, ranges are validated by the engine
+						Schema: format.schema.without(false, true),
+						Strict: true,
 					},
 				},
-				Temperature: baseTemperature,
-				MaxTokens:   2000,
 			},
 		)
-
 		if err != nil {
-			logger.Log.Warnf("OpenAI request failed attempt: %d: %v", attempt+1, err)
-			lastErr = fmt.Errorf("OpenAI API error: %w", err)
-			baseTemperature -= 0.02
-			continue
+			return "", tokens{}, err
 		}
 
-		content := resp.Choices[0].Message.Content
-
-		// Remove any markdown code block wrapping if exists
-		content = strings.TrimSpace(content)
-		content = strings.TrimPrefix(content, "```json")
-		content = strings.TrimPrefix(content, "```")
-		content = strings.TrimSuffix(content, "```")
-		content = strings.TrimSpace(content)
-
-		// Try to parse as generic JSON first
-		var jsonContent interface{}
-		if err := json.Unmarshal([]byte(content), &jsonContent); err != nil {
-			logger.Log.Warnf("Invalid JSON response: %v", err)
-			lastErr = fmt.Errorf("invalid JSON response: %w", err)
-			baseTemperature -= 0.02
-			continue
+		used := tokens{prompt: resp.Usage.PromptTokens, completion: resp.Usage.CompletionTokens}
+		if len(resp.Choices) == 0 {
+			return "", used, fmt.Errorf("empty response from %s", a.engine.provider)
 		}
-
-		return content, nil
-	}
-
-	return "", fmt.Errorf("failed after %d attempts: %w", maxRetries, lastErr)
+		return resp.Choices[0].Message.Content, used, nil
+	})
 }
 
 func (a *OpenAI) AnalyzeCode(ctx context.Context, question string, files []github.GitHubFile) (answer string, confidence float64, err error) {
-	systemPrompt := `You are a specialized AI code assistant with expertise in analyzing codebases and providing technical explanations.
-
-Your core responsibilities:
-1. Analyze code thoroughly and provide accurate, well-structured explanations
-2. Focus on practical, implementation-focused responses
-3. Always include relevant code examples and file references
-4. Maintain a professional and educational tone
-5. Ensure responses are complete and well-organized
-6. MUST return valid JSON with properly escaped strings (\\n for newlines, \" for quotes)
-
-When analyzing code:
-- Start with a high-level overview
-- Break down complex concepts into clear sections
-- Provide concrete examples for each explanation
-- Reference specific files and code sections
-- Include practical use cases and best practices
-
-Your responses should be:
-- Technical yet accessible
-- Well-structured with clear sections
-- Supported by code examples
-- Focused on practical implementation
-- Complete and self-contained`
-
-	userPrompt := fmt.Sprintf("Analyze the codebase and provide a response in the following JSON format (DO NOT wrap the response in code blocks):\n"+
-		"{\n"+
-		"  \"answer\": \"Your detailed explanation here. Structure your answer as follows:\\n\\n"+
-		"1. Start with a brief overview (2-3 sentences)\\n"+
-		"2. Break down the explanation into clear sections using markdown headers (###)\\n"+
-		"3. For each section:\\n"+
-		"   - Provide a clear explanation\\n"+
-		"   - Include relevant code examples\\n"+
-		"   - Explain when and how to use the feature\\n"+
-		"4. Add relevant code references\\n"+
-		"5. Include practical examples and use cases\\n\\n"+
-		"Use proper markdown formatting for better readability.\",\n"+
-		"  \"confidence\": 0.8,\n"+
-		"  \"relevant_files\": [\"path/to/file.ext\"]\n"+
-		"}\n"+
-		"\n"+
-		"Response Requirements:\n"+
-		"1. Make explanations comprehensive yet concise\n"+
-		"2. Use markdown headers (###) to organize content\n"+
-		"3. Include code examples with proper markdown code blocks\n"+
-		"4. Reference specific files and line numbers when relevant\n"+
-		"5. Provide practical usage examples\n"+
-		"6. Ensure the response is complete (no truncated sentences or examples)\n"+
-		"7. Return ONLY the JSON response, do not wrap it in markdown code blocks\n"+
-		"8. MUST escape all newlines with \\n and quotes with \\\n"+
-		"\n"+
-		"Available Files:\n"+
-		"%s\n"+
-		"\n"+
-		"Question:\n"+
-		"%s", formatFilesForPrompt(files), question)
-
-	logger.Log.Infof("Analyzing code: [%s] with %d files", question, len(files))
-
-	content, err := a.makeRequest(ctx, systemPrompt, userPrompt)
+	p, err := a.prompts.codePrompt(question, files)
 	if err != nil {
 		return "", 0, err
 	}
 
-	var aiResp AIResponse
-	if err := json.Unmarshal([]byte(content), &aiResp); err != nil {
-		return "", 0, fmt.Errorf("failed to parse AI response: %w", err)
+	logger.FromContext(ctx).Infof("Analyzing code with %d files", len(files))
+	logger.FromContext(ctx).Debugf("Analyzing code question: [%s]", question)
+
+	content, err := a.makeRequest(ctx, TaskCode, codeSchema, p)
+	if err != nil {
+		return "", 0, err
 	}
 
-	return aiResp.Answer, aiResp.Confidence, nil
+	return parseCodeAnalysis(content)
 }
 
 func (a *OpenAI) AnalyzeLabels(ctx context.Context, title, body string, availableLabels string) (github.LabelAnalysis, error) {
-	systemPrompt := `You are an AI assistant specialized in analyzing GitHub issues and suggesting appropriate labels.
-
-Your task is to:
-1. Analyze the issue title and body
-2. Consider the available labels and their descriptions
-3. Suggest relevant labels with confidence scores
-4. Provide brief explanations for your suggestions
-
-Guidelines:
-- Only suggest labels that are highly relevant
-- Consider both technical and non-technical aspects
-- Be conservative with confidence scores
-- Focus on the main topics and themes of the issue`
-
-	userPrompt := fmt.Sprintf("Analyze the issue and suggest appropriate labels. Provide your response in the following JSON format (DO NOT wrap in code blocks):\n"+
-		"{\n"+
-		"  \"suggestedLabels\": {\n"+
-		"    \"label-name\": 0.95,\n"+
-		"    \"another-label\": 0.85\n"+
-		"  },\n"+
-		"  \"explanation\": \"Brief explanation of why these labels were chosen\"\n"+
-		"}\n\n"+
-		"Confidence Score Guide:\n"+
-		"- 0.0-0.3: Weak relevance\n"+
-		"- 0.4-0.6: Moderate relevance\n"+
-		"- 0.7-0.9: Strong relevance\n"+
-		"- 1.0: Perfect match\n\n"+
-		"Issue Title: %s\nIssue Body:\n%s\n\nAvailable Labels:\n%s",
-		title, body, availableLabels)
-
-	logger.Log.Infof("Analyzing labels for issue: [%s]", title)
-
-	content, err := a.makeRequest(ctx, systemPrompt, userPrompt)
+	p, err := a.prompts.labelPrompt(title, body, availableLabels)
+	if err != nil {
+		return github.LabelAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Analyzing labels for issue: [%s]", title)
+
+	content, err := a.makeRequest(ctx, TaskLabels, labelSchema, p)
 	if err != nil {
 		return github.LabelAnalysis{}, err
 	}
 
-	var analysis github.LabelAnalysis
-	if err := json.Unmarshal([]byte(content), &analysis); err != nil {
-		return github.LabelAnalysis{}, fmt.Errorf("failed to parse label analysis: %w", err)
+	return parseLabelAnalysis(content)
+}
+
+func (a *OpenAI) AnalyzeDuplicates(ctx context.Context, title, body string, candidates []github.DuplicateCandidate) (github.DuplicateAnalysis, error) {
+	p, err := a.prompts.duplicatePrompt(title, body, candidates)
+	if err != nil {
+		return github.DuplicateAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Analyzing duplicates for issue: [%s] with %d candidates", title, len(candidates))
+
+	content, err := a.makeRequest(ctx, TaskDuplicates, duplicateSchema, p)
+	if err != nil {
+		return github.DuplicateAnalysis{}, err
+	}
+
+	return parseDuplicateAnalysis(content, candidates)
+}
+
+func (a *OpenAI) AnalyzeTriage(ctx context.Context, title, body string, components []string) (github.TriageAnalysis, error) {
+	p, err := a.prompts.triagePrompt(title, body, components)
+	if err != nil {
+		return github.TriageAnalysis{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Triaging issue: [%s] with %d components", title, len(components))
+
+	content, err := a.makeRequest(ctx, TaskTriage, triageSchema(components), p)
+	if err != nil {
+		return github.TriageAnalysis{}, err
 	}
 
-	return analysis, nil
+	return parseTriageAnalysis(content, components)
 }
 
-func formatFilesForPrompt(files []github.GitHubFile) string {
-	var result string
-	for _, file := range files {
-		result += fmt.Sprintf("File: %s\nContent:\n%s\n\n", file.Path, file.Content)
+func (a *OpenAI) AnalyzeRelevantFiles(ctx context.Context, title, body string, paths []string) ([]string, error) {
+	p, err := a.prompts.relevancePrompt(title, body, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Selecting relevant files for issue: [%s] from %d files", title, len(paths))
+
+	content, err := a.makeRequest(ctx, TaskRelevance, relevanceSchema, p)
+	if err != nil {
+		return nil, err
 	}
-	return result
+
+	return parseRelevantFiles(content, paths)
 }
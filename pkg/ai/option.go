@@ -0,0 +1,115 @@
+package ai
+
+import "net/http"
+
+// Option configures an AI service
+type Option func(*config)
+
+// config holds the provider settings that can be overridden with options
+type config struct {
+	endpoint         string
+	models           ModelConfig
+	taskModels       map[Task]ModelConfig
+	organization     string
+	apiVersion       string
+	azureDeployments map[string]string
+	prompts          *PromptRegistry
+	accountant       *Accountant
+	httpClient       *http.Client
+}
+
+// WithEndpoint sets the base URL of the provider API, e.g. "http://localhost:11434" for Ollama,
+// "https://my-resource.openai.azure.com" for Azure OpenAI or the URL of an OpenAI compatible gateway
+func WithEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithModel overrides the default model of the provider
+func WithModel(model string) Option {
+	return func(c *config) {
+		c.models.Model = model
+	}
+}
+
+// WithModelConfig overrides the default model, max tokens and temperature of the provider
+func WithModelConfig(models ModelConfig) Option {
+	return func(c *config) {
+		c.models = merge(c.models, models)
+	}
+}
+
+// WithTaskModelConfig overrides the model, max tokens and temperature used for a single task,
+// e.g. a cheaper model for labels and a stronger one for code analysis
+func WithTaskModelConfig(task Task, models ModelConfig) Option {
+	return func(c *config) {
+		if c.taskModels == nil {
+			c.taskModels = make(map[Task]ModelConfig)
+		}
+		c.taskModels[task] = merge(c.taskModels[task], models)
+	}
+}
+
+// WithOrganization sets the OpenAI organization ID sent with every request
+func WithOrganization(organization string) Option {
+	return func(c *config) {
+		c.organization = organization
+	}
+}
+
+// WithAPIVersion sets the Azure OpenAI API version (e.g. "2024-10-21")
+func WithAPIVersion(apiVersion string) Option {
+	return func(c *config) {
+		c.apiVersion = apiVersion
+	}
+}
+
+// WithAzureDeployment maps a model to the Azure OpenAI deployment serving it.
+// An empty model sets the deployment used for models without their own mapping.
+func WithAzureDeployment(model, deployment string) Option {
+	return func(c *config) {
+		if c.azureDeployments == nil {
+			c.azureDeployments = make(map[string]string)
+		}
+		c.azureDeployments[model] = deployment
+	}
+}
+
+// WithPromptRegistry sets the prompts the provider renders, so repository overrides
+// loaded into the registry apply to every provider sharing it
+func WithPromptRegistry(prompts *PromptRegistry) Option {
+	return func(c *config) {
+		c.prompts = prompts
+	}
+}
+
+// WithAccountant sets the accountant that records the token usage of the provider
+// and enforces the cost budget shared with the other providers of the run
+func WithAccountant(accountant *Accountant) Option {
+	return func(c *config) {
+		c.accountant = accountant
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to call the provider API, e.g. with a
+// proxy, custom timeouts or a record/replay transport
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+func newConfig(opts []Option) config {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.prompts == nil {
+		c.prompts = NewPromptRegistry()
+	}
+	if c.accountant == nil {
+		c.accountant = NewAccountant()
+	}
+	return c
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/workflowkit/issue-assistant/pkg/github"
+)
+
+type AIResponse struct {
+	Answer        string   `json:"answer"`
+	Confidence    float64  `json:"confidence" schema:"min=0,max=1"`
+	RelevantFiles []string `json:"relevant_files"`
+}
+
+// codeSchema is the structured output schema of code analysis
+var codeSchema = responseSchema{name: "code_analysis", schema: schemaFor(AIResponse{})}
+
+// codePromptData is available to the code analysis prompt template
+type codePromptData struct {
+	Question string
+	Files    string
+}
+
+// codePrompt renders the prompt of code analysis
+func (r *PromptRegistry) codePrompt(question string, files []github.GitHubFile) (prompt, error) {
+	return r.render(TaskCode, codePromptData{Question: untrusted("issue_body", question), Files: formatFilesForPrompt(files)})
+}
+
+// parseCodeAnalysis decodes the model response of code analysis
+func parseCodeAnalysis(content string) (string, float64, error) {
+	var aiResp AIResponse
+	if err := json.Unmarshal([]byte(content), &aiResp); err != nil {
+		return "", 0, fmt.Errorf("failed to parse AI response: %w", err)
+	}
+
+	return aiResp.Answer, aiResp.Confidence, nil
+}
+
+// labelSuggestion is a suggested label as returned by the model. Labels are a list
+// on the wire because strict schemas cannot describe maps with arbitrary keys.
+type labelSuggestion struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence" schema:"min=0,max=1"`
+}
+
+type labelResponse struct {
+	Labels      []labelSuggestion `json:"labels"`
+	Explanation string            `json:"explanation"`
+}
+
+// labelSchema is the structured output schema of label analysis
+var labelSchema = responseSchema{name: "label_analysis", schema: schemaFor(labelResponse{})}
+
+// labelPromptData is available to the label analysis prompt template
+type labelPromptData struct {
+	Title  string
+	Body   string
+	Labels string
+}
+
+// labelPrompt renders the prompt of label analysis
+func (r *PromptRegistry) labelPrompt(title, body string, availableLabels string) (prompt, error) {
+	return r.render(TaskLabels, labelPromptData{Title: untrustedTitle(title), Body: untrusted("issue_body", body), Labels: availableLabels})
+}
+
+// parseLabelAnalysis decodes the model response of label analysis
+func parseLabelAnalysis(content string) (github.LabelAnalysis, error) {
+	var response labelResponse
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		return github.LabelAnalysis{}, fmt.Errorf("failed to parse label analysis: %w", err)
+	}
+
+	analysis := github.LabelAnalysis{
+		SuggestedLabels: make(map[string]float64, len(response.Labels)),
+		Explanation:     response.Explanation,
+	}
+	for _, label := range response.Labels {
+		analysis.SuggestedLabels[label.Name] = label.Confidence
+	}
+
+	return analysis, nil
+}
+
+func formatFilesForPrompt(files []github.GitHubFile) string {
+	var result string
+	for _, file := range files {
+		result += untrustedFile(file.Path, file.Content) + "\n\n"
+	}
+	return result
+}
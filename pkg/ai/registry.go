@@ -0,0 +1,163 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+//go:embed prompts/*.tmpl
+var embeddedPrompts embed.FS
+
+// versionPattern reads the version header of a prompt template, e.g. {{/* version: 2 */}}
+var versionPattern = regexp.MustCompile(`^\s*{{/\*\s*version:\s*(\S+?)\s*\*/}}`)
+
+// promptFuncs are the functions available to prompt templates
+var promptFuncs = template.FuncMap{
+	"join":            strings.Join,
+	"untrustedNotice": func() string { return untrustedNotice },
+}
+
+// promptData is the data every task renders its prompt with, used to check overrides
+var promptData = map[Task]interface{}{
+	TaskCode:       codePromptData{},
+	TaskLabels:     labelPromptData{},
+	TaskDuplicates: duplicatePromptData{},
+	TaskTriage:     triagePromptData{},
+	TaskRelevance:  relevancePromptData{},
+}
+
+// prompt is a rendered prompt of a task
+type prompt struct {
+	system  string
+	user    string
+	version string
+}
+
+type promptTemplate struct {
+	version  string
+	template *template.Template
+}
+
+// PromptRegistry holds the versioned prompt template of every task. Templates
+// define a "system" and a "user" template and start with a version header.
+type PromptRegistry struct {
+	mu        sync.RWMutex
+	templates map[Task]promptTemplate
+	redact    func(string) string
+}
+
+// NewPromptRegistry creates a registry with the prompts embedded in the binary
+func NewPromptRegistry() *PromptRegistry {
+	r := &PromptRegistry{templates: make(map[Task]promptTemplate)}
+	for _, task := range Tasks {
+		content, err := embeddedPrompts.ReadFile(fmt.Sprintf("prompts/%s.tmpl", task))
+		if err != nil {
+			panic(fmt.Sprintf("missing embedded prompt for task %s", task))
+		}
+		parsed, err := parsePrompt(task, string(content))
+		if err != nil {
+			panic(err)
+		}
+		if parsed.version == "" {
+			panic(fmt.Sprintf("embedded prompt for task %s has no version", task))
+		}
+		r.templates[task] = parsed
+	}
+	return r
+}
+
+// Override replaces the prompt of a task, e.g. with a template from the repository.
+// Overrides without a version header are versioned by a hash of their content.
+func (r *PromptRegistry) Override(task Task, content string) error {
+	parsed, err := parsePrompt(task, content)
+	if err != nil {
+		return err
+	}
+	if parsed.version == "" {
+		sum := sha256.Sum256([]byte(content))
+		parsed.version = hex.EncodeToString(sum[:4])
+	}
+	parsed.version = "repo-" + parsed.version
+
+	// Render once so overrides referring to unknown fields fail now rather than on every request
+	for _, name := range []string{"system", "user"} {
+		if err := parsed.template.ExecuteTemplate(io.Discard, name, promptData[task]); err != nil {
+			return fmt.Errorf("invalid %s prompt: %w", task, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[task] = parsed
+	logger.Log.Infof("using repository prompt %s@%s", task, parsed.version)
+	return nil
+}
+
+// SetRedaction sets the function that removes secrets and personal data from rendered prompts
+// before they are sent to a provider
+func (r *PromptRegistry) SetRedaction(redact func(string) string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redact = redact
+}
+
+// Version returns the version of the prompt of a task, e.g. labels@1
+func (r *PromptRegistry) Version(task Task) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return fmt.Sprintf("%s@%s", task, r.templates[task].version)
+}
+
+// render executes the prompt template of a task with data
+func (r *PromptRegistry) render(task Task, data interface{}) (prompt, error) {
+	r.mu.RLock()
+	tmpl := r.templates[task]
+	redact := r.redact
+	r.mu.RUnlock()
+
+	var system, user strings.Builder
+	if err := tmpl.template.ExecuteTemplate(&system, "system", data); err != nil {
+		return prompt{}, fmt.Errorf("failed to render %s system prompt: %w", task, err)
+	}
+	if err := tmpl.template.ExecuteTemplate(&user, "user", data); err != nil {
+		return prompt{}, fmt.Errorf("failed to render %s user prompt: %w", task, err)
+	}
+
+	p := prompt{
+		system:  strings.TrimSpace(system.String()),
+		user:    strings.TrimSpace(user.String()),
+		version: fmt.Sprintf("%s@%s", task, tmpl.version),
+	}
+	if redact != nil {
+		p.system = redact(p.system)
+		p.user = redact(p.user)
+	}
+	return p, nil
+}
+
+func parsePrompt(task Task, content string) (promptTemplate, error) {
+	tmpl, err := template.New(string(task)).Funcs(promptFuncs).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return promptTemplate{}, fmt.Errorf("failed to parse %s prompt: %w", task, err)
+	}
+	for _, name := range []string{"system", "user"} {
+		if tmpl.Lookup(name) == nil {
+			return promptTemplate{}, fmt.Errorf("%s prompt does not define a %q template", task, name)
+		}
+	}
+
+	var version string
+	if match := versionPattern.FindStringSubmatch(content); match != nil {
+		version = match[1]
+	}
+	return promptTemplate{version: version, template: tmpl}, nil
+}
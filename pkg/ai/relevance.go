@@ -0,0 +1,49 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// maxRelevantFiles is the number of files the model may select
+const maxRelevantFiles = 10
+
+type relevanceResponse struct {
+	Files []string `json:"files"`
+}
+
+// relevanceSchema is the structured output schema of relevant file analysis
+var relevanceSchema = responseSchema{name: "relevant_files", schema: schemaFor(relevanceResponse{})}
+
+// relevancePromptData is available to the relevant file analysis prompt template
+type relevancePromptData struct {
+	Title    string
+	Body     string
+	MaxFiles int
+	Paths    []string
+}
+
+// relevancePrompt renders the prompt of relevant file analysis
+func (r *PromptRegistry) relevancePrompt(title, body string, paths []string) (prompt, error) {
+	return r.render(TaskRelevance, relevancePromptData{Title: untrustedTitle(title), Body: untrusted("issue_body", body), MaxFiles: maxRelevantFiles, Paths: paths})
+}
+
+// parseRelevantFiles decodes the model response and drops paths that are not in the repository
+func parseRelevantFiles(content string, paths []string) ([]string, error) {
+	var response relevanceResponse
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse relevant files: %w", err)
+	}
+
+	var files []string
+	for _, file := range response.Files {
+		if contains(paths, file) && !contains(files, file) {
+			files = append(files, file)
+		}
+	}
+	if len(files) > maxRelevantFiles {
+		files = files[:maxRelevantFiles]
+	}
+
+	return files, nil
+}
@@ -0,0 +1,326 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// retryPolicy controls how often and how long failed requests are retried
+type retryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	// maxRetryAfter is the longest Retry-After the engine waits for, longer
+	// waits fail the request so a fallback provider can take over
+	maxRetryAfter time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts:   4,
+	baseDelay:     time.Second,
+	maxDelay:      20 * time.Second,
+	maxRetryAfter: time.Minute,
+}
+
+// errInvalidOutput is returned when the model answer does not match the response schema
+var errInvalidOutput = errors.New("invalid response")
+
+// ErrAuthentication is matched by errors of requests the provider rejected because of the API key
+var ErrAuthentication = errors.New("AI provider authentication failed")
+
+// RequestError is returned when a provider request failed for good
+type RequestError struct {
+	// Provider is the name of the provider that failed
+	Provider string
+	// StatusCode is the HTTP status of the last response, 0 for transport errors
+	StatusCode int
+	// Attempts is the number of requests that were made
+	Attempts int
+	// Retryable reports whether the request gave up on an error that is worth retrying later
+	Retryable bool
+	Err       error
+}
+
+func (e *RequestError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s API error after %d attempts (status %d): %v", e.Provider, e.Attempts, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("%s API error after %d attempts: %v", e.Provider, e.Attempts, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrAuthentication for requests the provider rejected with 401 or 403
+func (e *RequestError) Is(target error) bool {
+	return target == ErrAuthentication &&
+		(e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden)
+}
+
+// permanentError marks an error that retrying the same request cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// permanent marks err as not retryable, e.g. a response blocked by a safety filter
+func permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// requestEngine sends provider requests, retrying transient failures with
+// exponential backoff and jitter and accounting the tokens they use
+type requestEngine struct {
+	provider   string
+	policy     retryPolicy
+	accountant *Accountant
+}
+
+func newRequestEngine(provider string, cfg config) requestEngine {
+	return requestEngine{provider: provider, policy: defaultRetryPolicy, accountant: cfg.accountant}
+}
+
+// request describes a provider request for the engine
+type request struct {
+	model     string
+	maxTokens int
+	prompt    prompt
+	schema    *Schema
+}
+
+// tokens are the token counts a provider reported for a response
+type tokens struct {
+	prompt     int
+	completion int
+}
+
+// do calls send until it returns content matching the schema, the error is fatal,
+// the attempts are used up or the context is done. The prompt version and token
+// usage of the requests are recorded in the metadata of the context.
+func (e requestEngine) do(ctx context.Context, req request, send func(ctx context.Context) (string, tokens, error)) (string, error) {
+	var lastErr error
+	var status int
+	md := MetadataFromContext(ctx)
+	log := logger.FromContext(ctx).With(logger.F("provider", e.provider))
+
+	log.Infof("using prompt %s", req.prompt.version)
+
+	for attempt := 1; attempt <= e.policy.maxAttempts; attempt++ {
+		reserved, err := e.accountant.reserve(req.model, estimateTokens(req.prompt.system, req.prompt.user), req.maxTokens)
+		if err != nil {
+			return "", &RequestError{Provider: e.provider, Attempts: attempt - 1, Err: err}
+		}
+
+		log := log.With(logger.F("attempt", attempt))
+		log.Debugf("making %s request: attempt %d", e.provider, attempt)
+
+		info := &responseInfo{}
+		content, used, err := send(context.WithValue(ctx, responseInfoKey{}, info))
+		// Tokens are paid for even when the answer turns out to be invalid
+		usage := e.accountant.record(md.Feature(), req.model, reserved, used.prompt, used.completion)
+		if usage.Requests > 0 {
+			md.addUsage(usage)
+			log.Debugf("%s request used %d prompt and %d completion tokens ($%.4f)",
+				e.provider, usage.PromptTokens, usage.CompletionTokens, usage.Cost)
+		}
+		if err == nil {
+			err = validateOutput(content, req.schema)
+			if err == nil {
+				md.setPrompt(req.prompt.version)
+				return content, nil
+			}
+		}
+		lastErr = err
+		status = info.statusCode()
+
+		if ctx.Err() != nil {
+			return "", &RequestError{Provider: e.provider, StatusCode: status, Attempts: attempt, Err: errors.Join(err, ctx.Err())}
+		}
+
+		retryable := isRetryable(err, status)
+		if !retryable {
+			return "", &RequestError{Provider: e.provider, StatusCode: status, Attempts: attempt, Err: err}
+		}
+		if attempt == e.policy.maxAttempts {
+			break
+		}
+
+		delay := e.policy.backoff(attempt)
+		if retryAfter := info.retryAfter(); retryAfter > 0 {
+			if retryAfter > e.policy.maxRetryAfter {
+				return "", &RequestError{Provider: e.provider, StatusCode: status, Attempts: attempt, Retryable: true,
+					Err: fmt.Errorf("rate limited for %s: %w", retryAfter, err)}
+			}
+			delay = retryAfter
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return "", &RequestError{Provider: e.provider, StatusCode: status, Attempts: attempt, Retryable: true,
+				Err: fmt.Errorf("no time left to retry before deadline: %w", err)}
+		}
+
+		log.Warnf("%s request failed attempt: %d, retrying in %s: %v", e.provider, attempt, delay.Round(time.Millisecond), err)
+		if err := sleep(ctx, delay); err != nil {
+			return "", &RequestError{Provider: e.provider, StatusCode: status, Attempts: attempt, Err: errors.Join(lastErr, err)}
+		}
+	}
+
+	return "", &RequestError{Provider: e.provider, StatusCode: status, Attempts: e.policy.maxAttempts, Retryable: true, Err: lastErr}
+}
+
+// backoff returns the delay before the next attempt, doubling per attempt with
+// jitter so concurrent runs do not retry in lockstep
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	delay := p.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isRetryable classifies a failed attempt. Rate limits, timeouts, server errors,
+// transport errors and malformed output are retried, authentication and invalid
+// requests are not.
+func isRetryable(err error, status int) bool {
+	var perm permanentError
+	if errors.As(err, &perm) {
+		return false
+	}
+	if errors.Is(err, errInvalidOutput) {
+		return true
+	}
+
+	switch {
+	case status == 0:
+		// The request never got a response, e.g. a connection reset
+		return true
+	case status == http.StatusRequestTimeout, status == http.StatusConflict, status == http.StatusTooManyRequests:
+		return true
+	case status >= 500:
+		return true
+	case status >= 400:
+		return false
+	default:
+		// A successful response with unusable content, e.g. no choices
+		return true
+	}
+}
+
+// validateOutput checks the structured output of the model against the response schema
+func validateOutput(content string, schema *Schema) error {
+	var value interface{}
+	if err := json.Unmarshal([]byte(content), &value); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidOutput, err)
+	}
+	if err := schema.Validate(value); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidOutput, err)
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// responseInfoKey is the context key of the responseInfo of an attempt
+type responseInfoKey struct{}
+
+// responseInfo captures the status and rate limit headers of the last HTTP
+// response of an attempt, independent of how the provider SDK reports errors
+type responseInfo struct {
+	mu     sync.Mutex
+	status int
+	header http.Header
+}
+
+func (i *responseInfo) record(resp *http.Response) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.status = resp.StatusCode
+	i.header = resp.Header
+}
+
+func (i *responseInfo) statusCode() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.status
+}
+
+// retryAfter returns how long the server asked to wait, 0 if it did not
+func (i *responseInfo) retryAfter() time.Duration {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.header == nil {
+		return 0
+	}
+
+	// OpenAI sends a millisecond precision variant next to the standard header
+	if ms, err := strconv.ParseFloat(i.header.Get("Retry-After-Ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+
+	value := i.header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+// recordingTransport records every response into the responseInfo of the request context
+type recordingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if resp != nil {
+		if info, ok := req.Context().Value(responseInfoKey{}).(*responseInfo); ok {
+			info.record(resp)
+		}
+	}
+	return resp, err
+}
+
+// newHTTPClient creates the HTTP client of a provider from the configured client,
+// reporting response status and rate limit headers to the request engine
+func newHTTPClient(cfg config) *http.Client {
+	client := &http.Client{}
+	if cfg.httpClient != nil {
+		*client = *cfg.httpClient
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client.Transport = &recordingTransport{base: base}
+	return client
+}
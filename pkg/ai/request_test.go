@@ -0,0 +1,306 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedResponse is a response of the scripted server
+type scriptedResponse struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+// scriptedServer serves the responses in order, repeating the last one
+type scriptedServer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	responses []scriptedResponse
+	requests  int
+}
+
+func newScriptedServer(t *testing.T, responses ...scriptedResponse) *scriptedServer {
+	s := &scriptedServer{responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		resp := s.responses[min(s.requests, len(s.responses)-1)]
+		s.requests++
+		s.mu.Unlock()
+
+		for name, value := range resp.header {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(resp.status)
+		fmt.Fprint(w, resp.body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *scriptedServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// send requests the scripted server like a provider client, failing on error statuses
+func (s *scriptedServer) send(ctx context.Context) (string, tokens, error) {
+	client := newHTTPClient(config{})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, nil)
+	if err != nil {
+		return "", tokens{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", tokens{}, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", tokens{}, fmt.Errorf("status %d: %s", resp.StatusCode, body)
+	}
+	return string(body), tokens{}, nil
+}
+
+var testRetryPolicy = retryPolicy{
+	maxAttempts:   3,
+	baseDelay:     time.Millisecond,
+	maxDelay:      5 * time.Millisecond,
+	maxRetryAfter: 200 * time.Millisecond,
+}
+
+var (
+	okResponse  = scriptedResponse{status: http.StatusOK, body: `{"answer":"ok"}`}
+	unavailable = scriptedResponse{status: http.StatusServiceUnavailable, body: "overloaded"}
+)
+
+func TestRequestEngineRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []scriptedResponse
+		// wantRequests is the number of requests the server received
+		wantRequests int
+		// wantErr is nil when the request succeeds
+		wantErr *RequestError
+		// wantMinDuration is the least time the retries take
+		wantMinDuration time.Duration
+	}{
+		{
+			name:         "success",
+			responses:    []scriptedResponse{okResponse},
+			wantRequests: 1,
+		},
+		{
+			name:         "server errors are retried",
+			responses:    []scriptedResponse{unavailable, {status: http.StatusBadGateway}, okResponse},
+			wantRequests: 3,
+		},
+		{
+			name:            "rate limit waits for Retry-After-Ms",
+			responses:       []scriptedResponse{{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After-Ms": "60"}}, okResponse},
+			wantRequests:    2,
+			wantMinDuration: 60 * time.Millisecond,
+		},
+		{
+			name:            "rate limit waits for Retry-After",
+			responses:       []scriptedResponse{{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "0.05"}}, okResponse},
+			wantRequests:    2,
+			wantMinDuration: 50 * time.Millisecond,
+		},
+		{
+			name:         "Retry-After longer than the limit gives up",
+			responses:    []scriptedResponse{{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "120"}}},
+			wantRequests: 1,
+			wantErr:      &RequestError{StatusCode: http.StatusTooManyRequests, Attempts: 1, Retryable: true},
+		},
+		{
+			name:         "invalid requests are not retried",
+			responses:    []scriptedResponse{{status: http.StatusBadRequest, body: "bad request"}},
+			wantRequests: 1,
+			wantErr:      &RequestError{StatusCode: http.StatusBadRequest, Attempts: 1},
+		},
+		{
+			name:         "rejected keys are not retried",
+			responses:    []scriptedResponse{{status: http.StatusUnauthorized, body: "invalid api key"}},
+			wantRequests: 1,
+			wantErr:      &RequestError{StatusCode: http.StatusUnauthorized, Attempts: 1},
+		},
+		{
+			name:         "attempts are used up",
+			responses:    []scriptedResponse{unavailable},
+			wantRequests: 3,
+			wantErr:      &RequestError{StatusCode: http.StatusServiceUnavailable, Attempts: 3, Retryable: true},
+		},
+		{
+			name:         "invalid output is retried",
+			responses:    []scriptedResponse{{status: http.StatusOK, body: `{"answer":1}`}, okResponse},
+			wantRequests: 2,
+		},
+		{
+			name:         "invalid output until attempts are used up",
+			responses:    []scriptedResponse{{status: http.StatusOK, body: "not json"}},
+			wantRequests: 3,
+			wantErr:      &RequestError{StatusCode: http.StatusOK, Attempts: 3, Retryable: true},
+		},
+	}
+
+	schema := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"answer": {Type: "string"}},
+		Required:   []string{"answer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newScriptedServer(t, tt.responses...)
+			e := requestEngine{provider: "test", policy: testRetryPolicy, accountant: NewAccountant()}
+			ctx, _ := WithMetadata(context.Background(), "label")
+
+			start := time.Now()
+			content, err := e.do(ctx, request{model: "model", schema: schema}, srv.send)
+			elapsed := time.Since(start)
+
+			if got := srv.count(); got != tt.wantRequests {
+				t.Errorf("server received %d requests, want %d", got, tt.wantRequests)
+			}
+			if elapsed < tt.wantMinDuration {
+				t.Errorf("took %s, want at least %s", elapsed, tt.wantMinDuration)
+			}
+			if tt.wantErr == nil {
+				if err != nil || content != okResponse.body {
+					t.Errorf("do = %q, %v, want %q", content, err, okResponse.body)
+				}
+				return
+			}
+
+			var requestErr *RequestError
+			if !errors.As(err, &requestErr) {
+				t.Fatalf("got %v, want a RequestError", err)
+			}
+			if requestErr.Provider != "test" || requestErr.StatusCode != tt.wantErr.StatusCode ||
+				requestErr.Attempts != tt.wantErr.Attempts || requestErr.Retryable != tt.wantErr.Retryable {
+				t.Errorf("got %+v, want status %d, %d attempts, retryable %v",
+					requestErr, tt.wantErr.StatusCode, tt.wantErr.Attempts, tt.wantErr.Retryable)
+			}
+			if got := errors.Is(err, ErrAuthentication); got != (tt.wantErr.StatusCode == http.StatusUnauthorized) {
+				t.Errorf("errors.Is(ErrAuthentication) = %v for status %d", got, tt.wantErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRequestEngineDeadline(t *testing.T) {
+	srv := newScriptedServer(t, scriptedResponse{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "0.15"}})
+	e := requestEngine{provider: "test", policy: testRetryPolicy, accountant: NewAccountant()}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Waiting for the rate limit would outlast the deadline, the engine gives up right away
+	start := time.Now()
+	_, err := e.do(ctx, request{model: "model", schema: &Schema{Type: "object"}}, srv.send)
+
+	var requestErr *RequestError
+	if !errors.As(err, &requestErr) || !requestErr.Retryable || requestErr.Attempts != 1 {
+		t.Fatalf("got %v, want a retryable RequestError after one attempt", err)
+	}
+	if !strings.Contains(err.Error(), "no time left") {
+		t.Errorf("error %q does not name the deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("took %s, want no wait before the deadline", elapsed)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		want   bool
+	}{
+		{"transport error", errors.New("connection reset"), 0, true},
+		{"request timeout", errors.New("timeout"), http.StatusRequestTimeout, true},
+		{"conflict", errors.New("conflict"), http.StatusConflict, true},
+		{"rate limit", errors.New("rate limited"), http.StatusTooManyRequests, true},
+		{"server error", errors.New("internal"), http.StatusInternalServerError, true},
+		{"bad request", errors.New("bad request"), http.StatusBadRequest, false},
+		{"unauthorized", errors.New("unauthorized"), http.StatusUnauthorized, false},
+		{"forbidden", errors.New("forbidden"), http.StatusForbidden, false},
+		{"unusable success", errors.New("no choices"), http.StatusOK, true},
+		{"invalid output", fmt.Errorf("%w: missing field", errInvalidOutput), http.StatusOK, true},
+		{"permanent", permanent(errors.New("blocked by safety filter")), http.StatusOK, false},
+		// A permanent error is not retried even when it is invalid output
+		{"permanent invalid output", permanent(fmt.Errorf("%w: refused", errInvalidOutput)), http.StatusOK, false},
+		{"permanent server error", permanent(errors.New("model not found")), http.StatusServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err, tt.status); got != tt.want {
+				t.Errorf("isRetryable(%v, %d) = %v, want %v", tt.err, tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := retryPolicy{baseDelay: time.Second, maxDelay: 20 * time.Second}
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 500 * time.Millisecond, time.Second},
+		{2, time.Second, 2 * time.Second},
+		{3, 2 * time.Second, 4 * time.Second},
+		{6, 10 * time.Second, 20 * time.Second},
+		// The shifted delay overflows, the maximum applies
+		{64, 10 * time.Second, 20 * time.Second},
+		{100, 10 * time.Second, 20 * time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			if got := p.backoff(tt.attempt); got < tt.min || got > tt.max {
+				t.Errorf("backoff(%d) = %s, want between %s and %s", tt.attempt, got, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"none", http.Header{}, 0},
+		{"seconds", http.Header{"Retry-After": {"2"}}, 2 * time.Second},
+		{"fractional seconds", http.Header{"Retry-After": {"0.5"}}, 500 * time.Millisecond},
+		{"milliseconds win", http.Header{"Retry-After": {"2"}, "Retry-After-Ms": {"150"}}, 150 * time.Millisecond},
+		{"negative", http.Header{"Retry-After": {"-1"}}, 0},
+		{"invalid", http.Header{"Retry-After": {"soon"}}, 0},
+		{"past date", http.Header{"Retry-After": {"Wed, 21 Oct 2015 07:28:00 GMT"}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &responseInfo{header: tt.header}
+			if got := info.retryAfter(); got != tt.want {
+				t.Errorf("retryAfter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	date := &responseInfo{header: http.Header{"Retry-After": {time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)}}}
+	if got := date.retryAfter(); got < 58*time.Second || got > time.Minute {
+		t.Errorf("retryAfter() of a date a minute ahead = %s", got)
+	}
+}
@@ -0,0 +1,217 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema the providers accept for structured output.
+// Schemas are generated from the response types with schemaFor.
+type Schema struct {
+	Type                 string             `json:"type"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// MarshalJSON lets a schema be passed where the OpenAI client expects a json.Marshaler
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	return json.Marshal((*plain)(s))
+}
+
+// schemaFor generates the schema of the JSON encoding of v. Every field is required
+// and unknown properties are rejected. Constraints are read from the schema tag,
+// e.g. `schema:"min=0,max=1"`, and descriptions from the desc tag.
+func schemaFor(v interface{}) *Schema {
+	return typeSchema(reflect.TypeOf(v))
+}
+
+func typeSchema(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: typeSchema(t.Elem())}
+	case reflect.Struct:
+		closed := false
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: &closed}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			property := typeSchema(field.Type)
+			property.Description = field.Tag.Get("desc")
+			applyConstraints(property, field.Tag.Get("schema"))
+
+			schema.Properties[name] = property
+			schema.Required = append(schema.Required, name)
+		}
+		return schema
+	default:
+		// Maps and interfaces cannot be expressed in the strict schemas providers accept
+		panic(fmt.Sprintf("unsupported schema type: %s", t))
+	}
+}
+
+func applyConstraints(schema *Schema, tag string) {
+	if tag == "" {
+		return
+	}
+	for _, constraint := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(constraint, "=")
+		switch key {
+		case "min", "max":
+			bound, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				panic(fmt.Sprintf("invalid schema bound %q", constraint))
+			}
+			if key == "min" {
+				schema.Minimum = &bound
+			} else {
+				schema.Maximum = &bound
+			}
+		case "maxItems":
+			limit, err := strconv.Atoi(value)
+			if err != nil {
+				panic(fmt.Sprintf("invalid schema limit %q", constraint))
+			}
+			schema.MaxItems = &limit
+		case "enum":
+			schema.Enum = strings.Split(value, "|")
+		default:
+			panic(fmt.Sprintf("unknown schema constraint %q", constraint))
+		}
+	}
+}
+
+// property returns the schema of a property of an object schema
+func (s *Schema) property(name string) *Schema {
+	return s.Properties[name]
+}
+
+// without returns a copy of the schema without the keywords a provider rejects
+func (s *Schema) without(additionalProperties, ranges bool) *Schema {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	if additionalProperties {
+		clone.AdditionalProperties = nil
+	}
+	if ranges {
+		clone.Minimum, clone.Maximum, clone.MaxItems = nil, nil, nil
+	}
+	clone.Items = s.Items.without(additionalProperties, ranges)
+	if s.Properties != nil {
+		clone.Properties = make(map[string]*Schema, len(s.Properties))
+		for name, property := range s.Properties {
+			clone.Properties[name] = property.without(additionalProperties, ranges)
+		}
+	}
+	return &clone
+}
+
+// Validate checks decoded JSON against the schema, so a provider that ignores
+// the schema cannot hand malformed results to the analyzers
+func (s *Schema) Validate(value interface{}) error {
+	return s.validate(value, "$")
+}
+
+func (s *Schema) validate(value interface{}, path string) error {
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: missing required field %q", path, name)
+			}
+		}
+		for name, item := range object {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: unexpected field %q", path, name)
+				}
+				continue
+			}
+			if err := property.validate(item, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array", path)
+		}
+		if s.MaxItems != nil && len(items) > *s.MaxItems {
+			return fmt.Errorf("%s: more than %d items", path, *s.MaxItems)
+		}
+		for i, item := range items {
+			if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string", path)
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, text) {
+			return fmt.Errorf("%s: %q is not one of %s", path, text, strings.Join(s.Enum, ", "))
+		}
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%s: expected %s", path, s.Type)
+		}
+		if s.Type == "integer" && number != float64(int64(number)) {
+			return fmt.Errorf("%s: expected integer", path)
+		}
+		if s.Minimum != nil && number < *s.Minimum {
+			return fmt.Errorf("%s: %v is below the minimum %v", path, number, *s.Minimum)
+		}
+		if s.Maximum != nil && number > *s.Maximum {
+			return fmt.Errorf("%s: %v is above the maximum %v", path, number, *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", path)
+		}
+	}
+	return nil
+}
+
+// responseSchema names the schema of a task response, providers use the name for
+// the response format or tool
+type responseSchema struct {
+	name   string
+	schema *Schema
+}
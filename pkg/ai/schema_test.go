@@ -0,0 +1,119 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	limit := 2
+	listSchema := &Schema{Type: "array", Items: &Schema{Type: "integer"}, MaxItems: &limit}
+	triage := triageSchema([]string{"pkg/ai"}).schema
+
+	tests := []struct {
+		name   string
+		schema *Schema
+		value  string
+		// wantErr is a part of the expected error, empty when the value is valid
+		wantErr string
+	}{
+		{"valid labels", labelSchema.schema, `{"labels":[{"name":"bug","confidence":0.9}],"explanation":"A crash."}`, ""},
+		{"empty labels", labelSchema.schema, `{"labels":[],"explanation":""}`, ""},
+		{"missing field", labelSchema.schema, `{"labels":[]}`, `$: missing required field "explanation"`},
+		{"missing nested field", labelSchema.schema, `{"labels":[{"name":"bug"}],"explanation":""}`, `$.labels[0]: missing required field "confidence"`},
+		{"extra property", labelSchema.schema, `{"labels":[],"explanation":"","reasoning":"..."}`, `$: unexpected field "reasoning"`},
+		{"extra nested property", labelSchema.schema, `{"labels":[{"name":"bug","confidence":1,"color":"red"}],"explanation":""}`, `$.labels[0]: unexpected field "color"`},
+		{"string instead of number", labelSchema.schema, `{"labels":[{"name":"bug","confidence":"high"}],"explanation":""}`, "$.labels[0].confidence: expected number"},
+		{"number instead of string", labelSchema.schema, `{"labels":[],"explanation":42}`, "$.explanation: expected string"},
+		{"object instead of array", labelSchema.schema, `{"labels":{"bug":0.9},"explanation":""}`, "$.labels: expected array"},
+		{"array instead of object", labelSchema.schema, `[]`, "$: expected object"},
+		{"null field", labelSchema.schema, `{"labels":null,"explanation":""}`, "$.labels: expected array"},
+		{"below minimum", labelSchema.schema, `{"labels":[{"name":"bug","confidence":-0.1}],"explanation":""}`, "below the minimum 0"},
+		{"above maximum", labelSchema.schema, `{"labels":[{"name":"bug","confidence":1.5}],"explanation":""}`, "above the maximum 1"},
+		{"valid triage", triage, `{"type":"bug","severity":"high","priority":"p1","component":"pkg/ai","reproducibility":"always","summary":"A crash."}`, ""},
+		{"unknown component is empty", triage, `{"type":"bug","severity":"high","priority":"p1","component":"","reproducibility":"always","summary":""}`, ""},
+		{"enum violation", triage, `{"type":"incident","severity":"high","priority":"p1","component":"","reproducibility":"always","summary":""}`, `$.type: "incident" is not one of bug, feature, question, docs`},
+		{"enum is case-sensitive", triage, `{"type":"bug","severity":"High","priority":"p1","component":"","reproducibility":"always","summary":""}`, `$.severity: "High" is not one of`},
+		{"component outside the repository", triage, `{"type":"bug","severity":"high","priority":"p1","component":"vendor","reproducibility":"always","summary":""}`, `$.component: "vendor" is not one of`},
+		{"integer", listSchema, `[1,2]`, ""},
+		{"fraction instead of integer", listSchema, `[1.5]`, "$[0]: expected integer"},
+		{"too many items", listSchema, `[1,2,3]`, "$: more than 2 items"},
+		{"boolean", &Schema{Type: "boolean"}, `"true"`, "$: expected boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatal(err)
+			}
+
+			err := tt.schema.Validate(value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate(%s) = %v, want valid", tt.value, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate(%s) = %v, want %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"valid", `{"files":["main.go"]}`, false},
+		{"not json", `Here are the files: main.go`, true},
+		{"truncated", `{"files":["main.go"`, true},
+		{"schema violation", `{"files":"main.go"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOutput(tt.content, relevanceSchema.schema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateOutput(%s) = %v, want error %v", tt.content, err, tt.wantErr)
+			}
+			// Invalid output is retried by the request engine
+			if err != nil && !errors.Is(err, errInvalidOutput) {
+				t.Errorf("error %v does not match errInvalidOutput", err)
+			}
+		})
+	}
+}
+
+func TestSchemaFor(t *testing.T) {
+	schema := schemaFor(AIResponse{})
+
+	if schema.Type != "object" || schema.AdditionalProperties == nil || *schema.AdditionalProperties {
+		t.Errorf("schema = %+v, want a closed object", schema)
+	}
+	if want := []string{"answer", "confidence", "relevant_files"}; !slices.Equal(schema.Required, want) {
+		t.Errorf("required = %v, want %v", schema.Required, want)
+	}
+	confidence := schema.property("confidence")
+	if confidence.Type != "number" || *confidence.Minimum != 0 || *confidence.Maximum != 1 {
+		t.Errorf("confidence = %+v, want a number between 0 and 1", confidence)
+	}
+	if files := schema.property("relevant_files"); files.Type != "array" || files.Items.Type != "string" {
+		t.Errorf("relevant_files = %+v, want an array of strings", files)
+	}
+
+	// Providers rejecting keywords get a copy without them, the original is unchanged
+	stripped := schema.without(true, true)
+	if stripped.AdditionalProperties != nil || stripped.property("confidence").Minimum != nil {
+		t.Errorf("stripped schema = %+v, want no additionalProperties and ranges", stripped)
+	}
+	if schema.AdditionalProperties == nil || schema.property("confidence").Minimum == nil {
+		t.Error("without modified the original schema")
+	}
+}
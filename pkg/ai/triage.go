@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/workflowkit/issue-assistant/pkg/github"
+)
+
+// Allowed values of the triage fields
+var (
+	TriageTypes           = []string{"bug", "feature", "question", "docs"}
+	TriageSeverities      = []string{"critical", "high", "medium", "low"}
+	TriagePriorities      = []string{"p0", "p1", "p2", "p3"}
+	TriageReproducibility = []string{"always", "intermittent", "unknown", "not-applicable"}
+)
+
+// triageSchema returns the structured output schema of triage analysis, restricting
+// every field to its allowed values
+func triageSchema(components []string) responseSchema {
+	schema := schemaFor(github.TriageAnalysis{})
+	schema.property("type").Enum = TriageTypes
+	schema.property("severity").Enum = TriageSeverities
+	schema.property("priority").Enum = TriagePriorities
+	schema.property("reproducibility").Enum = TriageReproducibility
+	schema.property("component").Enum = append([]string{""}, components...)
+	return responseSchema{name: "triage_analysis", schema: schema}
+}
+
+// triagePromptData is available to the triage analysis prompt template
+type triagePromptData struct {
+	Title           string
+	Body            string
+	Types           []string
+	Severities      []string
+	Priorities      []string
+	Reproducibility []string
+	Components      []string
+}
+
+// triagePrompt renders the prompt of triage analysis
+func (r *PromptRegistry) triagePrompt(title, body string, components []string) (prompt, error) {
+	return r.render(TaskTriage, triagePromptData{
+		Title:           untrustedTitle(title),
+		Body:            untrusted("issue_body", body),
+		Types:           TriageTypes,
+		Severities:      TriageSeverities,
+		Priorities:      TriagePriorities,
+		Reproducibility: TriageReproducibility,
+		Components:      components,
+	})
+}
+
+// parseTriageAnalysis decodes the model response and validates every field against its allowed values
+func parseTriageAnalysis(content string, components []string) (github.TriageAnalysis, error) {
+	var analysis github.TriageAnalysis
+	if err := json.Unmarshal([]byte(content), &analysis); err != nil {
+		return github.TriageAnalysis{}, fmt.Errorf("failed to parse triage analysis: %w", err)
+	}
+
+	fields := []struct {
+		name    string
+		value   *string
+		allowed []string
+	}{
+		{"type", &analysis.Type, TriageTypes},
+		{"severity", &analysis.Severity, TriageSeverities},
+		{"priority", &analysis.Priority, TriagePriorities},
+		{"reproducibility", &analysis.Reproducibility, TriageReproducibility},
+	}
+	for _, field := range fields {
+		*field.value = strings.ToLower(strings.TrimSpace(*field.value))
+		if !contains(field.allowed, *field.value) {
+			return github.TriageAnalysis{}, fmt.Errorf("invalid triage %s: %q", field.name, *field.value)
+		}
+	}
+
+	if analysis.Component != "" && !contains(components, analysis.Component) {
+		analysis.Component = ""
+	}
+
+	return analysis, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,245 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// ErrBudgetExceeded is returned instead of making a request that could exceed the cost budget of the run
+var ErrBudgetExceeded = errors.New("AI cost budget exceeded")
+
+// Usage is the token usage and cost of AI requests
+type Usage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+	Requests         int     `json:"requests"`
+}
+
+func (u Usage) add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		Cost:             u.Cost + other.Cost,
+		Requests:         u.Requests + other.Requests,
+	}
+}
+
+// Price is the cost of a model in USD per million tokens
+type Price struct {
+	Prompt     float64
+	Completion float64
+}
+
+// cost returns the cost of the given token counts
+func (p Price) cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.Prompt + float64(completionTokens)*p.Completion) / 1_000_000
+}
+
+// ParsePrice parses a price given as "prompt/completion" in USD per million tokens, e.g. "0.15/0.60"
+func ParsePrice(s string) (Price, error) {
+	prompt, completion, ok := strings.Cut(s, "/")
+	if !ok {
+		return Price{}, fmt.Errorf("invalid price %q, expected prompt/completion", s)
+	}
+	var p Price
+	var err error
+	if p.Prompt, err = strconv.ParseFloat(strings.TrimSpace(prompt), 64); err != nil {
+		return Price{}, fmt.Errorf("invalid prompt price %q", prompt)
+	}
+	if p.Completion, err = strconv.ParseFloat(strings.TrimSpace(completion), 64); err != nil {
+		return Price{}, fmt.Errorf("invalid completion price %q", completion)
+	}
+	if p.Prompt < 0 || p.Completion < 0 {
+		return Price{}, fmt.Errorf("price %q cannot be negative", s)
+	}
+	return p, nil
+}
+
+// DefaultPrices returns the list prices of the default models of the providers.
+// Models without a price, e.g. local models, are free unless a default price is set.
+func DefaultPrices() map[string]Price {
+	return map[string]Price{
+		"gpt-4o-mini":                {Prompt: 0.15, Completion: 0.60},
+		"gpt-4o":                     {Prompt: 2.50, Completion: 10.00},
+		"claude-3-5-haiku-20241022":  {Prompt: 0.80, Completion: 4.00},
+		"claude-3-5-sonnet-20241022": {Prompt: 3.00, Completion: 15.00},
+		"gemini-2.0-flash":           {Prompt: 0.10, Completion: 0.40},
+	}
+}
+
+// Accountant aggregates the usage of all AI requests of a run per feature and
+// enforces the cost budget of the run
+type Accountant struct {
+	mu     sync.Mutex
+	prices map[string]Price
+	// defaultPrice is the price of models missing from prices, nil counts them as free
+	defaultPrice *Price
+	budget       float64
+	total        Usage
+	// pending is the estimated cost of the requests in flight
+	pending   float64
+	byFeature map[string]Usage
+	unpriced  map[string]bool
+}
+
+// NewAccountant creates an accountant with the default prices and no budget
+func NewAccountant() *Accountant {
+	return &Accountant{
+		prices:    DefaultPrices(),
+		byFeature: make(map[string]Usage),
+		unpriced:  make(map[string]bool),
+	}
+}
+
+// SetPrice sets the price of a model, overriding the default
+func (a *Accountant) SetPrice(model string, price Price) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.prices[model] = price
+}
+
+// SetDefaultPrice sets the price of models without a price of their own,
+// e.g. Azure deployments or custom models, instead of counting them as free
+func (a *Accountant) SetDefaultPrice(price Price) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.defaultPrice = &price
+}
+
+// SetBudget sets the maximum cost of a run in USD, 0 disables the budget
+func (a *Accountant) SetBudget(budget float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.budget = budget
+}
+
+// CheckPrices returns an error when a budget is set and one of the models has no
+// price, the budget could not limit the requests to such a model
+func (a *Accountant) CheckPrices(models ...string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.budget <= 0 {
+		return nil
+	}
+
+	var unpriced []string
+	for _, model := range models {
+		if _, ok := a.price(model); !ok && !slices.Contains(unpriced, model) {
+			unpriced = append(unpriced, model)
+		}
+	}
+	if len(unpriced) > 0 {
+		return fmt.Errorf("no price for the models %q, a budget requires a price for every model or a default price", unpriced)
+	}
+	return nil
+}
+
+// price returns the price of a model, or the default price for models without one.
+// The caller holds a.mu.
+func (a *Accountant) price(model string) (Price, bool) {
+	if price, ok := a.prices[model]; ok {
+		return price, true
+	}
+	if a.defaultPrice != nil {
+		return *a.defaultPrice, true
+	}
+	if !a.unpriced[model] {
+		a.unpriced[model] = true
+		if a.budget > 0 {
+			logger.Log.Warnf("no price configured for model %s, the budget does not limit its requests", model)
+		} else {
+			logger.Log.Debugf("no price configured for model %s, counting it as free", model)
+		}
+	}
+	return Price{}, false
+}
+
+// Total returns the usage of the run
+func (a *Accountant) Total() Usage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.total
+}
+
+// ByFeature returns the usage of the run per feature
+func (a *Accountant) ByFeature() map[string]Usage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	usage := make(map[string]Usage, len(a.byFeature))
+	for feature, u := range a.byFeature {
+		usage[feature] = u
+	}
+	return usage
+}
+
+// Features returns the features with recorded usage in alphabetical order
+func (a *Accountant) Features() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	features := make([]string, 0, len(a.byFeature))
+	for feature := range a.byFeature {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	return features
+}
+
+// reserve reserves the estimated cost of a request within the budget and returns the
+// reserved amount, which record releases. Reservations keep concurrent requests from
+// passing the budget check together and exceeding the budget.
+func (a *Accountant) reserve(model string, promptTokens, maxTokens int) (float64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.budget <= 0 {
+		return 0, nil
+	}
+
+	price, _ := a.price(model)
+	estimate := price.cost(promptTokens, maxTokens)
+	if a.total.Cost+a.pending+estimate > a.budget {
+		return 0, fmt.Errorf("%w: spent $%.6f and reserved $%.6f of $%.6f, next %s request may cost up to $%.6f",
+			ErrBudgetExceeded, a.total.Cost, a.pending, a.budget, model, estimate)
+	}
+	a.pending += estimate
+	return estimate, nil
+}
+
+// record releases the reservation of a request and adds its usage to the run. It returns
+// the usage with its cost, which is empty when the request failed without using tokens.
+func (a *Accountant) record(feature, model string, reserved float64, promptTokens, completionTokens int) Usage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.pending = max(a.pending-reserved, 0)
+	if promptTokens == 0 && completionTokens == 0 {
+		return Usage{}
+	}
+
+	price, _ := a.price(model)
+	usage := Usage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		Cost:             price.cost(promptTokens, completionTokens),
+		Requests:         1,
+	}
+	a.total = a.total.add(usage)
+	a.byFeature[feature] = a.byFeature[feature].add(usage)
+	return usage
+}
+
+// estimateTokens roughly estimates the tokens of a prompt, about four characters per token
+func estimateTokens(texts ...string) int {
+	var length int
+	for _, text := range texts {
+		length += len(text)
+	}
+	return length/4 + 1
+}
@@ -0,0 +1,166 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAccountantReservesWithinBudget(t *testing.T) {
+	a := NewAccountant()
+	a.SetPrice("model", Price{Prompt: 1_000_000, Completion: 1_000_000}) // $1 per token
+	a.SetBudget(10)
+
+	first, err := a.reserve("model", 2, 2)
+	if err != nil {
+		t.Fatalf("first reservation failed: %v", err)
+	}
+	second, err := a.reserve("model", 2, 2)
+	if err != nil {
+		t.Fatalf("second reservation failed: %v", err)
+	}
+	// $8 are reserved by requests in flight, another $4 request would exceed the budget
+	if _, err := a.reserve("model", 2, 2); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("third reservation: got %v, want ErrBudgetExceeded", err)
+	}
+
+	// A failed request without tokens releases its reservation
+	if usage := a.record("label", "model", first, 0, 0); usage != (Usage{}) {
+		t.Errorf("failed request recorded usage %+v", usage)
+	}
+	third, err := a.reserve("model", 2, 2)
+	if err != nil {
+		t.Fatalf("reservation after release failed: %v", err)
+	}
+
+	// Completed requests swap their reservation for the actual cost
+	a.record("label", "model", second, 2, 1)
+	a.record("comment", "model", third, 2, 1)
+	if got := a.Total(); got.Cost != 6 || got.Requests != 2 {
+		t.Errorf("total = %+v, want $6 in 2 requests", got)
+	}
+	if _, err := a.reserve("model", 2, 2); err != nil {
+		t.Errorf("reservation within remaining budget failed: %v", err)
+	}
+	if _, err := a.reserve("model", 1, 0); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("reservation beyond budget: got %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestAccountantWithoutBudget(t *testing.T) {
+	a := NewAccountant()
+	reserved, err := a.reserve("gpt-4o", 1_000_000, 1_000_000)
+	if err != nil || reserved != 0 {
+		t.Fatalf("reserve = %v, %v, want no reservation without budget", reserved, err)
+	}
+	usage := a.record("comment", "gpt-4o", reserved, 1_000_000, 100_000)
+	if usage.Cost != 3.5 {
+		t.Errorf("cost = %v, want 3.5", usage.Cost)
+	}
+}
+
+// testEngine returns an engine making single attempts for a $1 per token model
+func testEngine(budget float64) (requestEngine, *Accountant) {
+	a := NewAccountant()
+	a.SetPrice("model", Price{Prompt: 1_000_000, Completion: 1_000_000})
+	a.SetBudget(budget)
+	return requestEngine{provider: "test", policy: retryPolicy{maxAttempts: 1}, accountant: a}, a
+}
+
+// testRequest is a request of about 2 prompt tokens with up to 2 completion tokens, costing up to $4
+var testRequest = request{model: "model", maxTokens: 2, prompt: prompt{user: "ping"}, schema: &Schema{Type: "object"}}
+
+func TestConcurrentRequestsWithinBudget(t *testing.T) {
+	e, a := testEngine(10)
+	ctx, _ := WithMetadata(context.Background(), "label")
+
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = e.do(ctx, testRequest, func(ctx context.Context) (string, tokens, error) {
+				<-release
+				return "{}", tokens{prompt: 2, completion: 2}, nil
+			})
+		}()
+	}
+	// Requests over budget fail right away, the others wait in flight
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	var made, rejected int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			made++
+		case errors.Is(err, ErrBudgetExceeded):
+			rejected++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if made != 2 || rejected != 3 {
+		t.Errorf("%d requests made and %d rejected, want 2 within the $10 budget and 3 rejected", made, rejected)
+	}
+	if got := a.Total(); got.Cost > 10 {
+		t.Errorf("total cost $%v exceeds the budget", got.Cost)
+	}
+}
+
+func TestFailedRequestReleasesReservation(t *testing.T) {
+	e, a := testEngine(4)
+	ctx, _ := WithMetadata(context.Background(), "label")
+
+	_, err := e.do(ctx, testRequest, func(ctx context.Context) (string, tokens, error) {
+		return "", tokens{}, permanent(errors.New("bad request"))
+	})
+	if err == nil || errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("got %v, want the request error", err)
+	}
+
+	// The whole budget is available again
+	if _, err := e.do(ctx, testRequest, func(ctx context.Context) (string, tokens, error) {
+		return "{}", tokens{prompt: 2, completion: 1}, nil
+	}); err != nil {
+		t.Fatalf("request after failure: %v", err)
+	}
+	if got := a.Total(); got.Cost != 3 || got.Requests != 1 {
+		t.Errorf("total = %+v, want only the $3 of the completed request", got)
+	}
+}
+
+func TestAccountantDefaultPrice(t *testing.T) {
+	a := NewAccountant()
+	a.SetBudget(1)
+	if err := a.CheckPrices("gpt-4o-mini", "my-deployment"); err == nil {
+		t.Error("budget accepted a model without a price")
+	}
+
+	a.SetDefaultPrice(Price{Prompt: 1, Completion: 2})
+	if err := a.CheckPrices("gpt-4o-mini", "my-deployment"); err != nil {
+		t.Errorf("CheckPrices with default price: %v", err)
+	}
+	// Models with a price of their own keep it
+	if usage := a.record("label", "gpt-4o-mini", 0, 1_000_000, 0); usage.Cost != 0.15 {
+		t.Errorf("priced model cost = %v, want 0.15", usage.Cost)
+	}
+	if usage := a.record("label", "my-deployment", 0, 1_000_000, 1_000_000); usage.Cost != 3 {
+		t.Errorf("unpriced model cost = %v, want 3 at the default price", usage.Cost)
+	}
+	// The estimate of an unpriced model counts against the budget
+	if _, err := a.reserve("my-deployment", 1_000_000, 1_000_000); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("reserve = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestAccountantCheckPricesWithoutBudget(t *testing.T) {
+	if err := NewAccountant().CheckPrices("llama3.1"); err != nil {
+		t.Errorf("CheckPrices without budget: %v", err)
+	}
+}
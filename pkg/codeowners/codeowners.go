@@ -0,0 +1,110 @@
+package codeowners
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+)
+
+// Locations are the paths GitHub reads a CODEOWNERS file from, in order of precedence
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule is a single CODEOWNERS line
+type Rule struct {
+	Pattern string
+	Owners  []string
+	regex   *regexp.Regexp
+}
+
+// Ruleset is a parsed CODEOWNERS file
+type Ruleset []Rule
+
+// Parse parses the content of a CODEOWNERS file, ignoring comments and invalid lines
+func Parse(content string) Ruleset {
+	var rules Ruleset
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		regex, err := regexp.Compile(patternToRegex(fields[0]))
+		if err != nil {
+			continue
+		}
+		rules = append(rules, Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			regex:   regex,
+		})
+	}
+
+	return rules
+}
+
+// Owners returns the owners of a file path. As in GitHub, the last matching rule wins.
+func (r Ruleset) Owners(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(r) - 1; i >= 0; i-- {
+		if r[i].regex.MatchString(path) {
+			return r[i].Owners
+		}
+	}
+	return nil
+}
+
+// patternToRegex converts a gitignore style CODEOWNERS pattern to a regular expression
+func patternToRegex(pattern string) string {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("(^|/)")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					expr.WriteString("(.*/)?")
+				} else {
+					expr.WriteString(".*")
+				}
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	switch {
+	case directory:
+		expr.WriteString("/")
+	case strings.HasSuffix(pattern, "/*"):
+		// "docs/*" matches files directly in docs, not in its subdirectories
+		expr.WriteString("$")
+	default:
+		// A pattern matches the path itself or everything below it when it names a directory
+		expr.WriteString("(/|$)")
+	}
+
+	return expr.String()
+}
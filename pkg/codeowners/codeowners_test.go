@@ -0,0 +1,118 @@
+package codeowners
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{"*", []string{"README.md", "src/app/main.go"}, nil},
+		{"*.js", []string{"app.js", "src/app.js", "src/lib/app.js"}, []string{"app.jsx", "app.js.map", "src/app.ts"}},
+		{"README.md", []string{"README.md", "docs/README.md"}, []string{"README.mdx", "docs/README.md.bak"}},
+		// A leading slash anchors the pattern at the repository root
+		{"/README.md", []string{"README.md"}, []string{"docs/README.md"}},
+		// A slash in the middle anchors the pattern too
+		{"docs/api", []string{"docs/api", "docs/api/index.md"}, []string{"src/docs/api/index.md", "docs/apis/index.md"}},
+		// Directory patterns match everything below the directory, unanchored ones at any depth
+		{"apps/", []string{"apps/web/main.go", "services/apps/main.go"}, []string{"apps", "myapps/main.go"}},
+		{"/build/logs/", []string{"build/logs/out.log", "build/logs/2024/out.log"}, []string{"src/build/logs/out.log", "build/logs"}},
+		// A name without slash matches a directory of that name anywhere
+		{"vendor", []string{"vendor/lib.go", "third_party/vendor/lib.go"}, []string{"vendored/lib.go"}},
+		// A single star does not cross directories
+		{"docs/*", []string{"docs/index.md", "docs/guide.md"}, []string{"docs/guide/setup.md", "docs"}},
+		{"src/*.go", []string{"src/main.go"}, []string{"src/pkg/util.go"}},
+		{"?.txt", []string{"a.txt", "notes/b.txt"}, []string{"ab.txt"}},
+		// Double stars match any number of directories
+		{"**/logs", []string{"logs/out.log", "build/logs/out.log", "a/b/logs/out.log"}, []string{"catalogs/out.log"}},
+		{"docs/**/*.md", []string{"docs/index.md", "docs/guide/setup.md", "docs/a/b/c.md"}, []string{"src/docs/index.md", "docs/guide/setup.txt"}},
+		{"src/**", []string{"src/main.go", "src/pkg/util.go"}, []string{"lib/src/main.go"}},
+		{"a/**/b", []string{"a/b", "a/x/b", "a/x/y/b/file"}, []string{"a/xb"}},
+		// Regular expression characters are literal
+		{"config.(dev).yml", []string{"config.(dev).yml"}, []string{"configX(dev)Xyml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			rules := Parse(tt.pattern + " @owner")
+			for _, path := range tt.matches {
+				if rules.Owners(path) == nil {
+					t.Errorf("%s does not match %s", tt.pattern, path)
+				}
+			}
+			for _, path := range tt.misses {
+				if rules.Owners(path) != nil {
+					t.Errorf("%s matches %s", tt.pattern, path)
+				}
+			}
+		})
+	}
+}
+
+func TestLastMatchWins(t *testing.T) {
+	rules := Parse(`# Default owners of everything
+*           @acme/core
+
+# Language owners
+*.js        @acme/frontend
+*.go        @acme/backend   # inline comment
+
+/docs/      @acme/docs @octocat
+/docs/api/  @acme/api
+
+# Generated files have no owners
+/docs/api/generated/
+`)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"Makefile", []string{"@acme/core"}},
+		{"web/app.js", []string{"@acme/frontend"}},
+		{"cmd/main.go", []string{"@acme/backend"}},
+		// Later rules win over earlier ones, however specific the earlier ones are
+		{"docs/guide.md", []string{"@acme/docs", "@octocat"}},
+		{"docs/app.js", []string{"@acme/docs", "@octocat"}},
+		{"docs/api/index.md", []string{"@acme/api"}},
+		{"/docs/api/index.md", []string{"@acme/api"}},
+		{"docs/api/generated/client.go", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := rules.Owners(tt.path)
+			if got == nil || !slices.Equal(got, tt.want) {
+				t.Errorf("Owners(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	// The same rules in reverse order let the catch-all win
+	reversed := Parse("/docs/ @acme/docs\n* @acme/core\n")
+	if got := reversed.Owners("docs/guide.md"); !slices.Equal(got, []string{"@acme/core"}) {
+		t.Errorf("Owners = %v, want the last matching rule", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	rules := Parse("\n# comment only\n   \n*.go @go-team\n/docs/\t@docs  @octocat # trailing\n")
+
+	if len(rules) != 2 {
+		t.Fatalf("parsed %d rules, want 2: %+v", len(rules), rules)
+	}
+	if rules[0].Pattern != "*.go" || !slices.Equal(rules[0].Owners, []string{"@go-team"}) {
+		t.Errorf("rule 0 = %+v", rules[0])
+	}
+	if rules[1].Pattern != "/docs/" || !slices.Equal(rules[1].Owners, []string{"@docs", "@octocat"}) {
+		t.Errorf("rule 1 = %+v", rules[1])
+	}
+
+	if owners := Parse("").Owners("main.go"); owners != nil {
+		t.Errorf("empty file owns main.go: %v", owners)
+	}
+}
@@ -0,0 +1,142 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// CachingService caches the results of repository reads for the lifetime of the
+// service, so features reading the same content during a run fetch it only once.
+// Concurrent reads of the same content wait for a single request. Writes and issue
+// searches are passed through. Cached results are shared between callers and must
+// not be modified.
+type CachingService struct {
+	GitHubService
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry is a read result, loading until done is closed
+type cacheEntry struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// NewCachingService wraps a service with a read cache
+func NewCachingService(service GitHubService) *CachingService {
+	return &CachingService{
+		GitHubService: service,
+		entries:       make(map[string]*cacheEntry),
+	}
+}
+
+// cached returns the cached result for key or loads and caches it. Errors are not cached.
+// Callers waiting for the load of another caller stop waiting when their own context is
+// done, and load again themselves when the load was aborted by the context of the other
+// caller, so a caller with a short time limit cannot fail the read for the others.
+func cached[T any](ctx context.Context, c *CachingService, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	for {
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		if !ok {
+			entry = &cacheEntry{done: make(chan struct{})}
+			c.entries[key] = entry
+			c.mu.Unlock()
+
+			value, err := load(ctx)
+			entry.value, entry.err = value, err
+			if err != nil {
+				c.mu.Lock()
+				delete(c.entries, key)
+				c.mu.Unlock()
+			}
+			close(entry.done)
+			return value, err
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+		if entry.err == nil {
+			return entry.value.(T), nil
+		}
+		if !errors.Is(entry.err, context.Canceled) && !errors.Is(entry.err, context.DeadlineExceeded) {
+			var zero T
+			return zero, entry.err
+		}
+	}
+}
+
+type labelsResult struct {
+	info   string
+	labels []*github.Label
+}
+
+type fileResult struct {
+	content string
+	found   bool
+}
+
+func (c *CachingService) GetRepositoryContent(ctx context.Context, owner, repo string) ([]GitHubFile, error) {
+	return cached(ctx, c, fmt.Sprintf("content/%s/%s", owner, repo), func(ctx context.Context) ([]GitHubFile, error) {
+		return c.GitHubService.GetRepositoryContent(ctx, owner, repo)
+	})
+}
+
+func (c *CachingService) GetRepositoryTree(ctx context.Context, owner, repo string) ([]string, error) {
+	return cached(ctx, c, fmt.Sprintf("tree/%s/%s", owner, repo), func(ctx context.Context) ([]string, error) {
+		return c.GitHubService.GetRepositoryTree(ctx, owner, repo)
+	})
+}
+
+func (c *CachingService) GetDirectoryFiles(ctx context.Context, owner, repo, path string) ([]GitHubFile, error) {
+	return cached(ctx, c, fmt.Sprintf("directory/%s/%s/%s", owner, repo, path), func(ctx context.Context) ([]GitHubFile, error) {
+		return c.GitHubService.GetDirectoryFiles(ctx, owner, repo, path)
+	})
+}
+
+func (c *CachingService) GetFile(ctx context.Context, owner, repo, path string) (string, bool, error) {
+	result, err := cached(ctx, c, fmt.Sprintf("file/%s/%s/%s", owner, repo, path), func(ctx context.Context) (fileResult, error) {
+		content, found, err := c.GitHubService.GetFile(ctx, owner, repo, path)
+		return fileResult{content: content, found: found}, err
+	})
+	return result.content, result.found, err
+}
+
+func (c *CachingService) GetCommitAuthors(ctx context.Context, owner, repo, path string, since time.Time, limit int) ([]string, error) {
+	key := fmt.Sprintf("commits/%s/%s/%s/%d/%d", owner, repo, path, since.Unix(), limit)
+	return cached(ctx, c, key, func(ctx context.Context) ([]string, error) {
+		return c.GitHubService.GetCommitAuthors(ctx, owner, repo, path, since, limit)
+	})
+}
+
+func (c *CachingService) GetLabelsForAIAnalysis(ctx context.Context, owner, repo string) (string, []*github.Label, error) {
+	result, err := cached(ctx, c, fmt.Sprintf("labels/%s/%s", owner, repo), func(ctx context.Context) (labelsResult, error) {
+		info, labels, err := c.GitHubService.GetLabelsForAIAnalysis(ctx, owner, repo)
+		return labelsResult{info: info, labels: labels}, err
+	})
+	return result.info, result.labels, err
+}
+
+func (c *CachingService) GetOpenMilestones(ctx context.Context, owner, repo string) ([]Milestone, error) {
+	return cached(ctx, c, fmt.Sprintf("milestones/%s/%s", owner, repo), func(ctx context.Context) ([]Milestone, error) {
+		return c.GitHubService.GetOpenMilestones(ctx, owner, repo)
+	})
+}
+
+func (c *CachingService) GetProject(ctx context.Context, owner string, number int) (*Project, error) {
+	return cached(ctx, c, fmt.Sprintf("project/%s/%d", owner, number), func(ctx context.Context) (*Project, error) {
+		return c.GitHubService.GetProject(ctx, owner, number)
+	})
+}
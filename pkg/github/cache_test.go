@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// treeService serves a repository tree, blocking each read until release is closed
+type treeService struct {
+	GitHubService
+	release chan struct{}
+	reads   atomic.Int32
+}
+
+func (s *treeService) GetRepositoryTree(ctx context.Context, owner, repo string) ([]string, error) {
+	s.reads.Add(1)
+	select {
+	case <-s.release:
+		return []string{"README.md"}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestCachingServiceSharesConcurrentReads(t *testing.T) {
+	service := &treeService{release: make(chan struct{})}
+	cache := NewCachingService(service)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if paths, err := cache.GetRepositoryTree(context.Background(), "acme", "widgets"); err != nil || len(paths) != 1 {
+				t.Errorf("GetRepositoryTree = %v, %v", paths, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(service.release)
+	wg.Wait()
+
+	if _, err := cache.GetRepositoryTree(context.Background(), "acme", "widgets"); err != nil {
+		t.Fatal(err)
+	}
+	if reads := service.reads.Load(); reads != 1 {
+		t.Errorf("service read the tree %d times, want once", reads)
+	}
+}
+
+func TestCachingServiceRetriesAbortedReads(t *testing.T) {
+	service := &treeService{release: make(chan struct{})}
+	cache := NewCachingService(service)
+
+	// The first caller gives up before the read completes
+	short, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := cache.GetRepositoryTree(short, "acme", "widgets")
+		waiter <- err
+	}()
+	for service.reads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		close(service.release)
+	}()
+
+	// A caller waiting for the aborted read reads again instead of failing
+	paths, err := cache.GetRepositoryTree(context.Background(), "acme", "widgets")
+	if err != nil || len(paths) != 1 {
+		t.Errorf("GetRepositoryTree = %v, %v, want the tree", paths, err)
+	}
+	if err := <-waiter; err == nil {
+		t.Error("the aborted read succeeded")
+	}
+	if reads := service.reads.Load(); reads != 2 {
+		t.Errorf("service read the tree %d times, want twice", reads)
+	}
+}
@@ -3,27 +3,69 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v45/github"
 	"golang.org/x/oauth2"
 )
 
 type Client struct {
-	client *github.Client
-	filter FileFilter
+	client     *github.Client
+	httpClient *http.Client
+	graphqlURL string
+	filter     FileFilter
 }
 
-func NewClient(token string) *Client {
+// ClientOption configures a GitHub client
+type ClientOption func(*Client) error
+
+// WithBaseURL points the client at another REST API, e.g. "https://github.example.com/api/v3"
+// for GitHub Enterprise Server or the URL of a fake server
+func WithBaseURL(apiURL string) ClientOption {
+	return func(c *Client) error {
+		baseURL, err := url.Parse(strings.TrimSuffix(apiURL, "/") + "/")
+		if err != nil {
+			return fmt.Errorf("invalid api url: %w", err)
+		}
+		c.client.BaseURL = baseURL
+		return nil
+	}
+}
+
+// WithGraphQLURL points the client at another GraphQL API, e.g. "https://github.example.com/api/graphql"
+func WithGraphQLURL(graphqlURL string) ClientOption {
+	return func(c *Client) error {
+		if _, err := url.Parse(graphqlURL); err != nil {
+			return fmt.Errorf("invalid graphql url: %w", err)
+		}
+		c.graphqlURL = graphqlURL
+		return nil
+	}
+}
+
+func NewClient(token string, opts ...ClientOption) (*Client, error) {
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(context.Background(), ts)
-	return &Client{
-		client: github.NewClient(tc),
-		filter: DefaultFileFilter(),
+	c := &Client{
+		client:     github.NewClient(tc),
+		httpClient: tc,
+		graphqlURL: defaultGraphQLURL,
+		filter:     DefaultFileFilter(),
 	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
 }
 
 func (c *Client) WithFileFilter(filter FileFilter) *Client {
@@ -200,3 +242,201 @@ func (c *Client) AddLabelsToIssue(ctx context.Context, owner, repo string, issue
 	}
 	return nil
 }
+
+// SearchIssues returns up to limit issues in the repository matching the given search qualifiers
+func (c *Client) SearchIssues(ctx context.Context, owner, repo, query string, limit int) ([]Issue, error) {
+	opts := &github.SearchOptions{
+		ListOptions: github.ListOptions{PerPage: limit},
+	}
+
+	result, _, err := c.client.Search.Issues(ctx, fmt.Sprintf("repo:%s/%s is:issue %s", owner, repo, query), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	var issues []Issue
+	for _, issue := range result.Issues {
+		if issue == nil || issue.Number == nil {
+			continue
+		}
+		issues = append(issues, Issue{
+			Number:   issue.GetNumber(),
+			Title:    issue.GetTitle(),
+			Body:     issue.GetBody(),
+			State:    issue.GetState(),
+			URL:      issue.GetHTMLURL(),
+			ClosedAt: issue.ClosedAt,
+		})
+	}
+
+	return issues, nil
+}
+
+// GetUser returns the account of a login
+func (c *Client) GetUser(ctx context.Context, login string) (*User, error) {
+	user, _, err := c.client.Users.Get(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &User{
+		Login:     user.GetLogin(),
+		Type:      user.GetType(),
+		CreatedAt: user.GetCreatedAt().Time,
+	}, nil
+}
+
+// GetDirectoryFiles returns the files directly inside a repository directory,
+// or no files when the directory does not exist
+func (c *Client) GetDirectoryFiles(ctx context.Context, owner, repo, path string) ([]GitHubFile, error) {
+	_, directoryContent, resp, err := c.client.Repositories.GetContents(ctx, owner, repo, path, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get contents: %w", err)
+	}
+
+	var files []GitHubFile
+	for _, content := range directoryContent {
+		if content == nil || content.GetType() != "file" || content.Path == nil {
+			continue
+		}
+
+		fileContent, err := c.getFileContent(ctx, owner, repo, *content.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file content for %s: %w", *content.Path, err)
+		}
+
+		files = append(files, GitHubFile{
+			Path:    *content.Path,
+			Content: fileContent,
+		})
+	}
+
+	return files, nil
+}
+
+// RemoveLabelFromIssue removes the specified label from an issue
+func (c *Client) RemoveLabelFromIssue(ctx context.Context, owner, repo string, issueNumber int, label string) error {
+	_, err := c.client.Issues.RemoveLabelForIssue(ctx, owner, repo, issueNumber, label)
+	if err != nil {
+		return fmt.Errorf("failed to remove label from issue: %w", err)
+	}
+	return nil
+}
+
+// GetRepositoryTree returns the paths of all files on the default branch of the repository
+func (c *Client) GetRepositoryTree(ctx context.Context, owner, repo string) ([]string, error) {
+	repository, _, err := c.client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	tree, _, err := c.client.Git.GetTree(ctx, owner, repo, repository.GetDefaultBranch(), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository tree: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" {
+			paths = append(paths, entry.GetPath())
+		}
+	}
+
+	return paths, nil
+}
+
+// GetFile returns the content of a single repository file, reporting false when it does not exist
+func (c *Client) GetFile(ctx context.Context, owner, repo, path string) (string, bool, error) {
+	fileContent, _, resp, err := c.client.Repositories.GetContents(ctx, owner, repo, path, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to get file contents: %w", err)
+	}
+
+	if fileContent == nil {
+		return "", false, nil
+	}
+
+	content, err := fileContent.GetContent()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decode content: %w", err)
+	}
+
+	return content, true, nil
+}
+
+// GetCommitAuthors returns the logins of the authors of commits touching path since the given time,
+// one entry per commit, most recent first
+func (c *Client) GetCommitAuthors(ctx context.Context, owner, repo, path string, since time.Time, limit int) ([]string, error) {
+	commits, _, err := c.client.Repositories.ListCommits(ctx, owner, repo, &github.CommitsListOptions{
+		Path:        path,
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: limit},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	var authors []string
+	for _, commit := range commits {
+		if login := commit.GetAuthor().GetLogin(); login != "" {
+			authors = append(authors, login)
+		}
+	}
+
+	return authors, nil
+}
+
+// AddAssignees assigns the specified users to an issue
+func (c *Client) AddAssignees(ctx context.Context, owner, repo string, issueNumber int, assignees []string) error {
+	_, _, err := c.client.Issues.AddAssignees(ctx, owner, repo, issueNumber, assignees)
+	if err != nil {
+		return fmt.Errorf("failed to add assignees to issue: %w", err)
+	}
+	return nil
+}
+
+// GetOpenMilestones returns the open milestones of the repository
+func (c *Client) GetOpenMilestones(ctx context.Context, owner, repo string) ([]Milestone, error) {
+	var milestones []Milestone
+	opts := &github.MilestoneListOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	for {
+		page, resp, err := c.client.Issues.ListMilestones(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list milestones: %w", err)
+		}
+		for _, milestone := range page {
+			milestones = append(milestones, Milestone{
+				Number: milestone.GetNumber(),
+				Title:  milestone.GetTitle(),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return milestones, nil
+}
+
+// SetIssueMilestone sets the milestone of an issue
+func (c *Client) SetIssueMilestone(ctx context.Context, owner, repo string, issueNumber, milestoneNumber int) error {
+	_, _, err := c.client.Issues.Edit(ctx, owner, repo, issueNumber, &github.IssueRequest{
+		Milestone: github.Int(milestoneNumber),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set issue milestone: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package github
+
+import (
+	"context"
+	"strings"
+
+	"github.com/workflowkit/issue-assistant/pkg/logger"
+)
+
+// DryRunItemID is the project item ID returned by DryRunService for issues it did not add to a project
+const DryRunItemID = "dry-run"
+
+// DryRunService passes reads through and logs writes instead of making them,
+// to preview what a run would change
+type DryRunService struct {
+	GitHubService
+}
+
+// NewDryRunService wraps a service so it makes no changes
+func NewDryRunService(service GitHubService) *DryRunService {
+	return &DryRunService{GitHubService: service}
+}
+
+func (d *DryRunService) AddLabelsToIssue(ctx context.Context, owner, repo string, issueNumber int, labels []string) error {
+	logger.FromContext(ctx).Infof("dry run: would add labels %s to %s/%s#%d", strings.Join(labels, ", "), owner, repo, issueNumber)
+	return nil
+}
+
+func (d *DryRunService) RemoveLabelFromIssue(ctx context.Context, owner, repo string, issueNumber int, label string) error {
+	logger.FromContext(ctx).Infof("dry run: would remove label %s from %s/%s#%d", label, owner, repo, issueNumber)
+	return nil
+}
+
+func (d *DryRunService) CreateIssueComment(ctx context.Context, owner, repo string, issueNumber int, comment string) error {
+	logger.FromContext(ctx).Infof("dry run: would comment on %s/%s#%d:\n%s", owner, repo, issueNumber, comment)
+	return nil
+}
+
+func (d *DryRunService) AddAssignees(ctx context.Context, owner, repo string, issueNumber int, assignees []string) error {
+	logger.FromContext(ctx).Infof("dry run: would assign %s to %s/%s#%d", strings.Join(assignees, ", "), owner, repo, issueNumber)
+	return nil
+}
+
+func (d *DryRunService) SetIssueMilestone(ctx context.Context, owner, repo string, issueNumber, milestoneNumber int) error {
+	logger.FromContext(ctx).Infof("dry run: would set milestone %d on %s/%s#%d", milestoneNumber, owner, repo, issueNumber)
+	return nil
+}
+
+func (d *DryRunService) AddIssueToProject(ctx context.Context, projectID, issueNodeID string) (string, error) {
+	logger.FromContext(ctx).Infof("dry run: would add issue %s to project %s", issueNodeID, projectID)
+	return DryRunItemID, nil
+}
+
+func (d *DryRunService) UpdateProjectItemField(ctx context.Context, projectID, itemID, fieldID string, value ProjectFieldValue) error {
+	logger.FromContext(ctx).Infof("dry run: would set field %s of project item %s to %s%s",
+		fieldID, itemID, value.SingleSelectOptionID, value.IterationID)
+	return nil
+}
@@ -0,0 +1,46 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// errUnauthorized is returned by GraphQL requests rejected with 401
+var errUnauthorized = errors.New("request not authorized")
+
+// IsAuthenticationError reports whether err was caused by GitHub rejecting the token
+// because it is invalid, expired or revoked. A 403 for a permission the token lacks,
+// e.g. "Resource not accessible by integration", only fails the request that needs it
+// and is not an authentication error, nor are rate limits.
+func IsAuthenticationError(err error) bool {
+	if errors.Is(err, errUnauthorized) {
+		return true
+	}
+
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) {
+		return false
+	}
+
+	var responseErr *github.ErrorResponse
+	if errors.As(err, &responseErr) && responseErr.Response != nil {
+		return isUnauthorized(responseErr.Response.StatusCode, responseErr.Message)
+	}
+	return false
+}
+
+// isUnauthorized reports whether a response rejected the credentials themselves
+func isUnauthorized(statusCode int, message string) bool {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return true
+	case http.StatusForbidden:
+		return strings.Contains(strings.ToLower(message), "bad credentials")
+	default:
+		return false
+	}
+}
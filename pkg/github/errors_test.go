@@ -0,0 +1,42 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+)
+
+func TestIsAuthenticationError(t *testing.T) {
+	response := func(status int, message string) error {
+		return fmt.Errorf("failed to add labels: %w", &github.ErrorResponse{
+			Response: &http.Response{StatusCode: status, Request: &http.Request{}},
+			Message:  message,
+		})
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"invalid token", response(http.StatusUnauthorized, "Bad credentials"), true},
+		{"forbidden bad credentials", response(http.StatusForbidden, "Bad credentials"), true},
+		{"missing permission", response(http.StatusForbidden, "Resource not accessible by integration"), false},
+		{"not found", response(http.StatusNotFound, "Not Found"), false},
+		{"rate limit", &github.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}}, false},
+		{"secondary rate limit", &github.AbuseRateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}}, false},
+		{"graphql", fmt.Errorf("%w: status code 401", errUnauthorized), true},
+		{"other", errors.New("connection reset"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthenticationError(tt.err); got != tt.want {
+				t.Errorf("IsAuthenticationError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package githubtest
+
+import (
+	"time"
+
+	"github.com/workflowkit/issue-assistant/pkg/github"
+)
+
+// Repository is a fixture repository served by the fake server
+type Repository struct {
+	Owner string
+	Name  string
+	// DefaultBranch defaults to "main"
+	DefaultBranch string
+	// Fork marks the repository as a fork in issue events
+	Fork bool
+	// Files maps paths on the default branch to their content
+	Files      map[string]string
+	Labels     []Label
+	Issues     []Issue
+	Milestones []Milestone
+	Commits    []Commit
+}
+
+// Label is a repository label
+type Label struct {
+	Name        string
+	Description string
+}
+
+// Issue is an issue of a fixture repository. Mutations made through the fake
+// server are applied to it.
+type Issue struct {
+	Number int
+	// NodeID defaults to "ISSUE_<number>"
+	NodeID string
+	Title  string
+	Body   string
+	Author string
+	// AuthorAssociation is the author_association of the issue events, e.g. "CONTRIBUTOR"
+	AuthorAssociation string
+	State             string
+	CreatedAt         time.Time
+	ClosedAt          *time.Time
+	Labels            []string
+	Assignees         []string
+	Milestone         int
+}
+
+// User is an account served by the users API of the fake server
+type User struct {
+	Login string
+	// Type defaults to "User"
+	Type      string
+	CreatedAt time.Time
+}
+
+// Milestone is a repository milestone
+type Milestone struct {
+	Number int
+	Title  string
+	// State defaults to "open"
+	State string
+}
+
+// Commit is a commit of a fixture repository, reported by the commits API for its paths
+type Commit struct {
+	SHA    string
+	Author string
+	Date   time.Time
+	Paths  []string
+}
+
+// Project is a Projects (v2) board served by the GraphQL API of the fake server
+type Project struct {
+	Owner  string
+	Number int
+	github.Project
+}
+
+// Mutation kinds recorded by the fake server
+const (
+	MutationComment            = "comment"
+	MutationAddLabels          = "add-labels"
+	MutationRemoveLabel        = "remove-label"
+	MutationAddAssignees       = "add-assignees"
+	MutationSetMilestone       = "set-milestone"
+	MutationAddProjectItem     = "add-project-item"
+	MutationUpdateProjectField = "update-project-field"
+)
+
+// Mutation is a write made through the fake server
+type Mutation struct {
+	// Kind is one of the Mutation* constants
+	Kind string
+	// Repo is the "owner/name" of the repository, empty for project mutations
+	Repo string
+	// Issue is the issue number, 0 for project mutations
+	Issue int
+	// Values are the written values: the comment body, label names, assignee logins,
+	// the milestone number, the project and content IDs of an added item or the
+	// project, item, field and value IDs of an updated field
+	Values []string
+}
@@ -0,0 +1,146 @@
+package githubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// handleGraphQL serves the project query and mutations of github.Client, selected
+// by the root field of the query
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var request graphqlRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var data interface{}
+	var err error
+	switch {
+	case strings.Contains(request.Query, "projectV2(number"):
+		data = s.projectData(request.Variables)
+	case strings.Contains(request.Query, "addProjectV2ItemById"):
+		data, err = s.addProjectItem(request.Variables)
+	case strings.Contains(request.Query, "updateProjectV2ItemFieldValue"):
+		data, err = s.updateProjectItemField(request.Variables)
+	default:
+		err = fmt.Errorf("unsupported query")
+	}
+
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"errors": []map[string]string{{"message": err.Error()}},
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+func (s *Server) projectData(variables map[string]interface{}) interface{} {
+	owner, _ := variables["owner"].(string)
+	number, _ := variables["number"].(float64)
+
+	for _, project := range s.projects {
+		if project.Owner != owner || project.Number != int(number) {
+			continue
+		}
+
+		var fields []map[string]interface{}
+		for _, field := range project.Fields {
+			node := map[string]interface{}{"id": field.ID, "name": field.Name}
+			if len(field.Iterations) > 0 {
+				var iterations []map[string]interface{}
+				for _, iteration := range field.Iterations {
+					iterations = append(iterations, map[string]interface{}{
+						"id":        iteration.ID,
+						"title":     iteration.Title,
+						"startDate": iteration.StartDate.Format("2006-01-02"),
+						"duration":  iteration.Duration,
+					})
+				}
+				node["configuration"] = map[string]interface{}{"iterations": iterations}
+			} else {
+				var options []map[string]string
+				for _, option := range field.Options {
+					options = append(options, map[string]string{"id": option.ID, "name": option.Name})
+				}
+				node["options"] = options
+			}
+			fields = append(fields, node)
+		}
+
+		return map[string]interface{}{
+			"repositoryOwner": map[string]interface{}{
+				"projectV2": map[string]interface{}{
+					"id":     project.ID,
+					"title":  project.Title,
+					"fields": map[string]interface{}{"nodes": fields},
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{"repositoryOwner": map[string]interface{}{"projectV2": nil}}
+}
+
+func (s *Server) addProjectItem(variables map[string]interface{}) (interface{}, error) {
+	projectID, _ := variables["projectId"].(string)
+	contentID, _ := variables["contentId"].(string)
+	if !s.hasProject(projectID) {
+		return nil, fmt.Errorf("could not resolve to a node with the global id of '%s'", projectID)
+	}
+
+	s.record(Mutation{
+		Kind:   MutationAddProjectItem,
+		Values: []string{projectID, contentID},
+	})
+	return map[string]interface{}{
+		"addProjectV2ItemById": map[string]interface{}{
+			"item": map[string]string{"id": "ITEM_" + contentID},
+		},
+	}, nil
+}
+
+func (s *Server) updateProjectItemField(variables map[string]interface{}) (interface{}, error) {
+	projectID, _ := variables["projectId"].(string)
+	itemID, _ := variables["itemId"].(string)
+	fieldID, _ := variables["fieldId"].(string)
+	if !s.hasProject(projectID) {
+		return nil, fmt.Errorf("could not resolve to a node with the global id of '%s'", projectID)
+	}
+
+	value, _ := json.Marshal(variables["value"])
+	var fieldValue struct {
+		SingleSelectOptionID string `json:"singleSelectOptionId"`
+		IterationID          string `json:"iterationId"`
+	}
+	_ = json.Unmarshal(value, &fieldValue)
+
+	s.record(Mutation{
+		Kind:   MutationUpdateProjectField,
+		Values: []string{projectID, itemID, fieldID, fieldValue.SingleSelectOptionID + fieldValue.IterationID},
+	})
+	return map[string]interface{}{
+		"updateProjectV2ItemFieldValue": map[string]interface{}{
+			"projectV2Item": map[string]string{"id": itemID},
+		},
+	}, nil
+}
+
+func (s *Server) hasProject(id string) bool {
+	for _, project := range s.projects {
+		if project.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package githubtest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// issueQuery is the subset of the GitHub issue search syntax understood by the fake server:
+// repo:, is:open, is:closed, author:, created:>=date, closed:>=date, in: and search terms joined by OR
+type issueQuery struct {
+	repo         string
+	state        string
+	author       string
+	createdSince time.Time
+	closedSince  time.Time
+	terms        []string
+}
+
+func parseIssueQuery(q string) issueQuery {
+	var query issueQuery
+	for _, field := range strings.Fields(q) {
+		key, value, qualified := strings.Cut(field, ":")
+		switch {
+		case qualified && key == "repo":
+			query.repo = value
+		case qualified && key == "is" && (value == "open" || value == "closed"):
+			query.state = value
+		case qualified && key == "author":
+			query.author = value
+		case qualified && key == "created" && strings.HasPrefix(value, ">="):
+			query.createdSince = parseSearchDate(strings.TrimPrefix(value, ">="))
+		case qualified && key == "closed" && strings.HasPrefix(value, ">="):
+			query.closedSince = parseSearchDate(strings.TrimPrefix(value, ">="))
+		case qualified && (key == "is" || key == "in"):
+			// is:issue and in:title,body match every fixture issue
+		case field == "OR":
+		default:
+			query.terms = append(query.terms, strings.ToLower(field))
+		}
+	}
+	return query
+}
+
+// parseSearchDate parses a date or a date and time of a search qualifier
+func parseSearchDate(value string) time.Time {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	t, _ := time.Parse("2006-01-02", value)
+	return t
+}
+
+// matches reports whether an issue matches the query, any term matches the title or body
+func (q issueQuery) matches(issue Issue) bool {
+	if q.state != "" && issue.State != q.state {
+		return false
+	}
+	if q.author != "" && !strings.EqualFold(issue.Author, q.author) {
+		return false
+	}
+	if !q.createdSince.IsZero() && issue.CreatedAt.Before(q.createdSince) {
+		return false
+	}
+	if !q.closedSince.IsZero() && (issue.ClosedAt == nil || issue.ClosedAt.Before(q.closedSince)) {
+		return false
+	}
+	if len(q.terms) == 0 {
+		return true
+	}
+
+	text := strings.ToLower(issue.Title + " " + issue.Body)
+	for _, term := range q.terms {
+		if strings.Contains(text, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := parseIssueQuery(r.URL.Query().Get("q"))
+	repo, ok := s.repos[query.repo]
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	var matches []Issue
+	for _, issue := range repo.Issues {
+		if query.matches(issue) {
+			matches = append(matches, issue)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Number > matches[j].Number
+	})
+
+	total := len(matches)
+	if perPage, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && perPage > 0 && len(matches) > perPage {
+		matches = matches[:perPage]
+	}
+
+	items := []map[string]interface{}{}
+	for _, issue := range matches {
+		items = append(items, issueObject(query.repo, issue))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total_count":        total,
+		"incomplete_results": false,
+		"items":              items,
+	})
+}
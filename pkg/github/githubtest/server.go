@@ -0,0 +1,660 @@
+package githubtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/workflowkit/issue-assistant/pkg/github"
+)
+
+// Server is an in-process fake of the GitHub REST and GraphQL APIs backed by
+// fixture repositories. It serves the endpoints used by github.Client and records
+// every mutation so tests can assert on the exact writes a run made.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	repos     map[string]*Repository
+	users     map[string]User
+	projects  []Project
+	mutations []Mutation
+	nextID    int
+	// rejections are the responses of mutation kinds failing with an error
+	rejections map[string]rejection
+}
+
+// rejection is an error response of a rejected mutation
+type rejection struct {
+	status  int
+	message string
+}
+
+// NewServer starts a fake server serving the given repositories
+func NewServer(repos ...Repository) *Server {
+	s := &Server{
+		repos:      make(map[string]*Repository),
+		users:      make(map[string]User),
+		rejections: make(map[string]rejection),
+	}
+	for _, repo := range repos {
+		repo := repo
+		if repo.DefaultBranch == "" {
+			repo.DefaultBranch = "main"
+		}
+		for i := range repo.Issues {
+			if repo.Issues[i].NodeID == "" {
+				repo.Issues[i].NodeID = fmt.Sprintf("ISSUE_%d", repo.Issues[i].Number)
+			}
+			if repo.Issues[i].State == "" {
+				repo.Issues[i].State = "open"
+			}
+		}
+		s.repos[repo.Owner+"/"+repo.Name] = &repo
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/{owner}/{repo}", s.handleRepository)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/contents/{path...}", s.handleContents)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/git/trees/{sha}", s.handleTree)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/labels", s.handleLabels)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/milestones", s.handleMilestones)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/commits", s.handleCommits)
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/{number}", s.handleEditIssue)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/comments", s.handleComment)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/labels", s.handleAddLabels)
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/issues/{number}/labels/{name}", s.handleRemoveLabel)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/assignees", s.handleAddAssignees)
+	mux.HandleFunc("GET /search/issues", s.handleSearch)
+	mux.HandleFunc("GET /users/{login}", s.handleUser)
+	mux.HandleFunc("POST /graphql", s.handleGraphQL)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "Not Found")
+	})
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// AddProject adds a Projects (v2) board to the GraphQL API
+func (s *Server) AddProject(project Project) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.projects = append(s.projects, project)
+}
+
+// AddUser adds an account to the users API, also used for the user type in issue events
+func (s *Server) AddUser(user User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user.Type == "" {
+		user.Type = "User"
+	}
+	s.users[user.Login] = user
+}
+
+// Reject makes the REST mutations of a kind fail with the given status and message,
+// e.g. 403 "Resource not accessible by integration" for a token lacking a permission
+func (s *Server) Reject(kind string, status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejections[kind] = rejection{status: status, message: message}
+}
+
+// Client returns a GitHub client talking to the fake server
+func (s *Server) Client() (*github.Client, error) {
+	return github.NewClient("test-token",
+		github.WithBaseURL(s.URL),
+		github.WithGraphQLURL(s.URL+"/graphql"))
+}
+
+// Mutations returns the recorded mutations in order
+func (s *Server) Mutations() []Mutation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mutation(nil), s.mutations...)
+}
+
+// MutationsOf returns the recorded mutations of a kind
+func (s *Server) MutationsOf(kind string) []Mutation {
+	var mutations []Mutation
+	for _, mutation := range s.Mutations() {
+		if mutation.Kind == kind {
+			mutations = append(mutations, mutation)
+		}
+	}
+	return mutations
+}
+
+// Issue returns the current state of a fixture issue
+func (s *Server) Issue(owner, repo string, number int) (Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.issue(owner, repo, number)
+	if issue == nil {
+		return Issue{}, false
+	}
+	return *issue, true
+}
+
+// WriteEvent writes a GitHub issues event for a fixture issue to path, to be
+// passed to the helper as event path
+func (s *Server) WriteEvent(path, action, owner, repo string, number int) error {
+	issue, ok := s.Issue(owner, repo, number)
+	if !ok {
+		return fmt.Errorf("issue %s/%s#%d not found", owner, repo, number)
+	}
+
+	s.mu.Lock()
+	fork := s.repos[owner+"/"+repo].Fork
+	userType := "User"
+	if user, ok := s.users[issue.Author]; ok {
+		userType = user.Type
+	}
+	s.mu.Unlock()
+
+	event := map[string]interface{}{
+		"action": action,
+		"issue": map[string]interface{}{
+			"number":  issue.Number,
+			"node_id": issue.NodeID,
+			"title":   issue.Title,
+			"body":    issue.Body,
+			"labels":  labelObjects(issue.Labels),
+			"user":    map[string]string{"login": issue.Author, "type": userType},
+
+			"author_association": issue.AuthorAssociation,
+		},
+		"repository": map[string]interface{}{
+			"name":  repo,
+			"owner": map[string]string{"login": owner},
+			"fork":  fork,
+		},
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// rejected writes the error response of a rejected mutation kind, the caller holds s.mu
+func (s *Server) rejected(w http.ResponseWriter, kind string) bool {
+	rejection, ok := s.rejections[kind]
+	if ok {
+		writeError(w, rejection.status, rejection.message)
+	}
+	return ok
+}
+
+func (s *Server) record(mutation Mutation) {
+	s.mutations = append(s.mutations, mutation)
+}
+
+// repo returns the fixture repository of a request, writing a 404 when it does not exist
+func (s *Server) repo(w http.ResponseWriter, r *http.Request) *Repository {
+	repo, ok := s.repos[r.PathValue("owner")+"/"+r.PathValue("repo")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return nil
+	}
+	return repo
+}
+
+func (s *Server) issue(owner, repo string, number int) *Issue {
+	fixture, ok := s.repos[owner+"/"+repo]
+	if !ok {
+		return nil
+	}
+	for i := range fixture.Issues {
+		if fixture.Issues[i].Number == number {
+			return &fixture.Issues[i]
+		}
+	}
+	return nil
+}
+
+// requestIssue returns the issue of a request, writing a 404 when it does not exist
+func (s *Server) requestIssue(w http.ResponseWriter, r *http.Request) *Issue {
+	number, _ := strconv.Atoi(r.PathValue("number"))
+	issue := s.issue(r.PathValue("owner"), r.PathValue("repo"), number)
+	if issue == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+	return issue
+}
+
+func (s *Server) handleRepository(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":           repo.Name,
+		"full_name":      repo.Owner + "/" + repo.Name,
+		"default_branch": repo.DefaultBranch,
+		"owner":          map[string]string{"login": repo.Owner},
+	})
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[r.PathValue("login")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"login":      user.Login,
+		"type":       user.Type,
+		"created_at": user.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+func (s *Server) handleContents(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	target := strings.Trim(r.PathValue("path"), "/")
+	if content, ok := repo.Files[target]; ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"type":     "file",
+			"name":     path.Base(target),
+			"path":     target,
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+		return
+	}
+
+	prefix := ""
+	if target != "" {
+		prefix = target + "/"
+	}
+	entries := make(map[string]string)
+	for file := range repo.Files {
+		if !strings.HasPrefix(file, prefix) {
+			continue
+		}
+		name, _, isDir := strings.Cut(strings.TrimPrefix(file, prefix), "/")
+		if isDir {
+			entries[name] = "dir"
+		} else {
+			entries[name] = "file"
+		}
+	}
+	if len(entries) == 0 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var listing []map[string]string
+	for _, name := range names {
+		listing = append(listing, map[string]string{
+			"type": entries[name],
+			"name": name,
+			"path": prefix + name,
+		})
+	}
+	writeJSON(w, http.StatusOK, listing)
+}
+
+func (s *Server) handleTree(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	paths := make([]string, 0, len(repo.Files))
+	for file := range repo.Files {
+		paths = append(paths, file)
+	}
+	sort.Strings(paths)
+
+	var entries []map[string]string
+	for _, file := range paths {
+		entries = append(entries, map[string]string{"path": file, "type": "blob", "mode": "100644"})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"sha":       r.PathValue("sha"),
+		"tree":      entries,
+		"truncated": false,
+	})
+}
+
+func (s *Server) handleLabels(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	labels := []map[string]string{}
+	for _, label := range repo.Labels {
+		labels = append(labels, map[string]string{"name": label.Name, "description": label.Description})
+	}
+	writeJSON(w, http.StatusOK, labels)
+}
+
+func (s *Server) handleMilestones(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	milestones := []map[string]interface{}{}
+	for _, milestone := range repo.Milestones {
+		milestoneState := milestone.State
+		if milestoneState == "" {
+			milestoneState = "open"
+		}
+		if state != "" && state != "all" && state != milestoneState {
+			continue
+		}
+		milestones = append(milestones, map[string]interface{}{
+			"number": milestone.Number,
+			"title":  milestone.Title,
+			"state":  milestoneState,
+		})
+	}
+	writeJSON(w, http.StatusOK, milestones)
+}
+
+func (s *Server) handleCommits(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := strings.Trim(query.Get("path"), "/")
+	var since time.Time
+	if value := query.Get("since"); value != "" {
+		since, _ = time.Parse(time.RFC3339, value)
+	}
+
+	commits := append([]Commit(nil), repo.Commits...)
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Date.After(commits[j].Date)
+	})
+
+	result := []map[string]interface{}{}
+	for _, commit := range commits {
+		if commit.Date.Before(since) || (filter != "" && !touches(commit, filter)) {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"sha":    commit.SHA,
+			"author": map[string]string{"login": commit.Author},
+			"commit": map[string]interface{}{
+				"author": map[string]string{"date": commit.Date.Format(time.RFC3339)},
+			},
+		})
+	}
+	if perPage, err := strconv.Atoi(query.Get("per_page")); err == nil && perPage > 0 && len(result) > perPage {
+		result = result[:perPage]
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func touches(commit Commit, filter string) bool {
+	for _, file := range commit.Paths {
+		if file == filter || strings.HasPrefix(file, filter+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleEditIssue(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Milestone *int `json:"milestone"`
+	}
+	if !decode(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.requestIssue(w, r)
+	if issue == nil {
+		return
+	}
+	if s.rejected(w, MutationSetMilestone) {
+		return
+	}
+
+	if request.Milestone != nil {
+		issue.Milestone = *request.Milestone
+		s.record(Mutation{
+			Kind:   MutationSetMilestone,
+			Repo:   r.PathValue("owner") + "/" + r.PathValue("repo"),
+			Issue:  issue.Number,
+			Values: []string{strconv.Itoa(*request.Milestone)},
+		})
+	}
+	writeJSON(w, http.StatusOK, issueObject(r.PathValue("owner")+"/"+r.PathValue("repo"), *issue))
+}
+
+func (s *Server) handleComment(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Body string `json:"body"`
+	}
+	if !decode(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.requestIssue(w, r)
+	if issue == nil {
+		return
+	}
+	if s.rejected(w, MutationComment) {
+		return
+	}
+
+	s.nextID++
+	s.record(Mutation{
+		Kind:   MutationComment,
+		Repo:   r.PathValue("owner") + "/" + r.PathValue("repo"),
+		Issue:  issue.Number,
+		Values: []string{request.Body},
+	})
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"id": s.nextID, "body": request.Body})
+}
+
+func (s *Server) handleAddLabels(w http.ResponseWriter, r *http.Request) {
+	var labels []string
+	if !decode(w, r, &labels) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.requestIssue(w, r)
+	if issue == nil {
+		return
+	}
+	if s.rejected(w, MutationAddLabels) {
+		return
+	}
+
+	for _, label := range labels {
+		if !containsFold(issue.Labels, label) {
+			issue.Labels = append(issue.Labels, label)
+		}
+	}
+	s.record(Mutation{
+		Kind:   MutationAddLabels,
+		Repo:   r.PathValue("owner") + "/" + r.PathValue("repo"),
+		Issue:  issue.Number,
+		Values: labels,
+	})
+	writeJSON(w, http.StatusOK, labelObjects(issue.Labels))
+}
+
+func (s *Server) handleRemoveLabel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.requestIssue(w, r)
+	if issue == nil {
+		return
+	}
+	if s.rejected(w, MutationRemoveLabel) {
+		return
+	}
+
+	name := r.PathValue("name")
+	var remaining []string
+	for _, label := range issue.Labels {
+		if !strings.EqualFold(label, name) {
+			remaining = append(remaining, label)
+		}
+	}
+	if len(remaining) == len(issue.Labels) {
+		writeError(w, http.StatusNotFound, "Label does not exist")
+		return
+	}
+	issue.Labels = remaining
+
+	s.record(Mutation{
+		Kind:   MutationRemoveLabel,
+		Repo:   r.PathValue("owner") + "/" + r.PathValue("repo"),
+		Issue:  issue.Number,
+		Values: []string{name},
+	})
+	writeJSON(w, http.StatusOK, labelObjects(issue.Labels))
+}
+
+func (s *Server) handleAddAssignees(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Assignees []string `json:"assignees"`
+	}
+	if !decode(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.requestIssue(w, r)
+	if issue == nil {
+		return
+	}
+	if s.rejected(w, MutationAddAssignees) {
+		return
+	}
+
+	for _, assignee := range request.Assignees {
+		if !containsFold(issue.Assignees, assignee) {
+			issue.Assignees = append(issue.Assignees, assignee)
+		}
+	}
+	s.record(Mutation{
+		Kind:   MutationAddAssignees,
+		Repo:   r.PathValue("owner") + "/" + r.PathValue("repo"),
+		Issue:  issue.Number,
+		Values: request.Assignees,
+	})
+	writeJSON(w, http.StatusCreated, issueObject(r.PathValue("owner")+"/"+r.PathValue("repo"), *issue))
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Problems parsing JSON: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+func issueObject(repo string, issue Issue) map[string]interface{} {
+	object := map[string]interface{}{
+		"number":    issue.Number,
+		"node_id":   issue.NodeID,
+		"title":     issue.Title,
+		"body":      issue.Body,
+		"state":     issue.State,
+		"html_url":  fmt.Sprintf("https://github.com/%s/issues/%d", repo, issue.Number),
+		"labels":    labelObjects(issue.Labels),
+		"user":      map[string]string{"login": issue.Author},
+		"assignees": loginObjects(issue.Assignees),
+	}
+	if !issue.CreatedAt.IsZero() {
+		object["created_at"] = issue.CreatedAt.Format(time.RFC3339)
+	}
+	if issue.ClosedAt != nil {
+		object["closed_at"] = issue.ClosedAt.Format(time.RFC3339)
+	}
+	if issue.Milestone != 0 {
+		object["milestone"] = map[string]int{"number": issue.Milestone}
+	}
+	return object
+}
+
+func labelObjects(labels []string) []map[string]string {
+	objects := []map[string]string{}
+	for _, label := range labels {
+		objects = append(objects, map[string]string{"name": label})
+	}
+	return objects
+}
+
+func loginObjects(logins []string) []map[string]string {
+	objects := []map[string]string{}
+	for _, login := range logins {
+		objects = append(objects, map[string]string{"login": login})
+	}
+	return objects
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
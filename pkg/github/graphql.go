@@ -0,0 +1,227 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const defaultGraphQLURL = "https://api.github.com/graphql"
+
+const projectQuery = `query($owner: String!, $number: Int!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        title
+        fields(first: 100) {
+          nodes {
+            ... on ProjectV2SingleSelectField { id name options { id name } }
+            ... on ProjectV2IterationField { id name configuration { iterations { id title startDate duration } } }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const addProjectItemMutation = `mutation($projectId: ID!, $contentId: ID!) {
+  addProjectV2ItemById(input: {projectId: $projectId, contentId: $contentId}) {
+    item { id }
+  }
+}`
+
+const updateProjectItemFieldMutation = `mutation($projectId: ID!, $itemId: ID!, $fieldId: ID!, $value: ProjectV2FieldValue!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $projectId, itemId: $itemId, fieldId: $fieldId, value: $value}) {
+    projectV2Item { id }
+  }
+}`
+
+// GetProject returns a Projects (v2) board of an organization or user with its single select and iteration fields
+func (c *Client) GetProject(ctx context.Context, owner string, number int) (*Project, error) {
+	var result struct {
+		RepositoryOwner *struct {
+			ProjectV2 *struct {
+				ID     string `json:"id"`
+				Title  string `json:"title"`
+				Fields struct {
+					Nodes []struct {
+						ID      string `json:"id"`
+						Name    string `json:"name"`
+						Options []struct {
+							ID   string `json:"id"`
+							Name string `json:"name"`
+						} `json:"options"`
+						Configuration *struct {
+							Iterations []struct {
+								ID        string `json:"id"`
+								Title     string `json:"title"`
+								StartDate string `json:"startDate"`
+								Duration  int    `json:"duration"`
+							} `json:"iterations"`
+						} `json:"configuration"`
+					} `json:"nodes"`
+				} `json:"fields"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+
+	err := c.graphql(ctx, projectQuery, map[string]interface{}{
+		"owner":  owner,
+		"number": number,
+	}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if result.RepositoryOwner == nil || result.RepositoryOwner.ProjectV2 == nil {
+		return nil, fmt.Errorf("project %d not found for %s", number, owner)
+	}
+
+	project := &Project{
+		ID:    result.RepositoryOwner.ProjectV2.ID,
+		Title: result.RepositoryOwner.ProjectV2.Title,
+	}
+	for _, node := range result.RepositoryOwner.ProjectV2.Fields.Nodes {
+		if node.ID == "" {
+			continue // Field types other than single select and iteration
+		}
+
+		field := ProjectField{ID: node.ID, Name: node.Name}
+		for _, option := range node.Options {
+			field.Options = append(field.Options, ProjectFieldOption{ID: option.ID, Name: option.Name})
+		}
+		if node.Configuration != nil {
+			for _, iteration := range node.Configuration.Iterations {
+				startDate, err := time.Parse("2006-01-02", iteration.StartDate)
+				if err != nil {
+					return nil, fmt.Errorf("invalid start date of iteration %s: %w", iteration.Title, err)
+				}
+				field.Iterations = append(field.Iterations, ProjectIteration{
+					ID:        iteration.ID,
+					Title:     iteration.Title,
+					StartDate: startDate,
+					Duration:  iteration.Duration,
+				})
+			}
+			slices.SortFunc(field.Iterations, func(a, b ProjectIteration) int {
+				return a.StartDate.Compare(b.StartDate)
+			})
+		}
+		project.Fields = append(project.Fields, field)
+	}
+
+	return project, nil
+}
+
+// AddIssueToProject adds an issue, identified by its node ID, to a project and returns the project item ID
+func (c *Client) AddIssueToProject(ctx context.Context, projectID, issueNodeID string) (string, error) {
+	var result struct {
+		AddProjectV2ItemByID struct {
+			Item struct {
+				ID string `json:"id"`
+			} `json:"item"`
+		} `json:"addProjectV2ItemById"`
+	}
+
+	err := c.graphql(ctx, addProjectItemMutation, map[string]interface{}{
+		"projectId": projectID,
+		"contentId": issueNodeID,
+	}, &result)
+	if err != nil {
+		return "", fmt.Errorf("failed to add issue to project: %w", err)
+	}
+
+	return result.AddProjectV2ItemByID.Item.ID, nil
+}
+
+// UpdateProjectItemField sets a single select or iteration field of a project item
+func (c *Client) UpdateProjectItemField(ctx context.Context, projectID, itemID, fieldID string, value ProjectFieldValue) error {
+	fieldValue := map[string]string{}
+	switch {
+	case value.SingleSelectOptionID != "":
+		fieldValue["singleSelectOptionId"] = value.SingleSelectOptionID
+	case value.IterationID != "":
+		fieldValue["iterationId"] = value.IterationID
+	default:
+		return errors.New("project field value is empty")
+	}
+
+	err := c.graphql(ctx, updateProjectItemFieldMutation, map[string]interface{}{
+		"projectId": projectID,
+		"itemId":    itemID,
+		"fieldId":   fieldID,
+		"value":     fieldValue,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update project item field: %w", err)
+	}
+
+	return nil
+}
+
+// graphql executes a GraphQL query against the GitHub API and decodes its data into result
+func (c *Client) graphql(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.graphqlURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&failure)
+		if isUnauthorized(resp.StatusCode, failure.Message) {
+			return fmt.Errorf("%w: status code %d: %s", errUnauthorized, resp.StatusCode, failure.Message)
+		}
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, failure.Message)
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		messages := make([]string, 0, len(response.Errors))
+		for _, e := range response.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("graphql error: %s", strings.Join(messages, "; "))
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Data, result); err != nil {
+		return fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return nil
+}
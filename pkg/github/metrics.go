@@ -0,0 +1,203 @@
+package github
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// CallMetrics are the calls of a service method
+type CallMetrics struct {
+	Calls    int
+	Errors   int
+	Duration time.Duration
+}
+
+// MetricsService counts the calls, errors and time spent per method of a service
+type MetricsService struct {
+	service GitHubService
+
+	mu      sync.Mutex
+	methods map[string]CallMetrics
+}
+
+var _ GitHubService = (*MetricsService)(nil)
+
+// NewMetricsService wraps a service with call metrics
+func NewMetricsService(service GitHubService) *MetricsService {
+	return &MetricsService{
+		service: service,
+		methods: make(map[string]CallMetrics),
+	}
+}
+
+// Metrics returns the metrics per method name
+func (m *MetricsService) Metrics() map[string]CallMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics := make(map[string]CallMetrics, len(m.methods))
+	for method, call := range m.methods {
+		metrics[method] = call
+	}
+	return metrics
+}
+
+// Methods returns the called method names in alphabetical order
+func (m *MetricsService) Methods() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	methods := make([]string, 0, len(m.methods))
+	for method := range m.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// Total returns the metrics of all methods
+func (m *MetricsService) Total() CallMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var total CallMetrics
+	for _, call := range m.methods {
+		total.Calls += call.Calls
+		total.Errors += call.Errors
+		total.Duration += call.Duration
+	}
+	return total
+}
+
+// observe records a call of method that started at start
+func (m *MetricsService) observe(method string, start time.Time, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	call := m.methods[method]
+	call.Calls++
+	call.Duration += time.Since(start)
+	if err != nil {
+		call.Errors++
+	}
+	m.methods[method] = call
+}
+
+func (m *MetricsService) GetRepositoryContent(ctx context.Context, owner, repo string) ([]GitHubFile, error) {
+	start := time.Now()
+	files, err := m.service.GetRepositoryContent(ctx, owner, repo)
+	m.observe("GetRepositoryContent", start, err)
+	return files, err
+}
+
+func (m *MetricsService) GetRepositoryTree(ctx context.Context, owner, repo string) ([]string, error) {
+	start := time.Now()
+	paths, err := m.service.GetRepositoryTree(ctx, owner, repo)
+	m.observe("GetRepositoryTree", start, err)
+	return paths, err
+}
+
+func (m *MetricsService) GetDirectoryFiles(ctx context.Context, owner, repo, path string) ([]GitHubFile, error) {
+	start := time.Now()
+	files, err := m.service.GetDirectoryFiles(ctx, owner, repo, path)
+	m.observe("GetDirectoryFiles", start, err)
+	return files, err
+}
+
+func (m *MetricsService) GetFile(ctx context.Context, owner, repo, path string) (string, bool, error) {
+	start := time.Now()
+	content, found, err := m.service.GetFile(ctx, owner, repo, path)
+	m.observe("GetFile", start, err)
+	return content, found, err
+}
+
+func (m *MetricsService) GetCommitAuthors(ctx context.Context, owner, repo, path string, since time.Time, limit int) ([]string, error) {
+	start := time.Now()
+	authors, err := m.service.GetCommitAuthors(ctx, owner, repo, path, since, limit)
+	m.observe("GetCommitAuthors", start, err)
+	return authors, err
+}
+
+func (m *MetricsService) GetLabelsForAIAnalysis(ctx context.Context, owner, repo string) (string, []*github.Label, error) {
+	start := time.Now()
+	info, labels, err := m.service.GetLabelsForAIAnalysis(ctx, owner, repo)
+	m.observe("GetLabelsForAIAnalysis", start, err)
+	return info, labels, err
+}
+
+func (m *MetricsService) AddLabelsToIssue(ctx context.Context, owner, repo string, issueNumber int, labels []string) error {
+	start := time.Now()
+	err := m.service.AddLabelsToIssue(ctx, owner, repo, issueNumber, labels)
+	m.observe("AddLabelsToIssue", start, err)
+	return err
+}
+
+func (m *MetricsService) RemoveLabelFromIssue(ctx context.Context, owner, repo string, issueNumber int, label string) error {
+	start := time.Now()
+	err := m.service.RemoveLabelFromIssue(ctx, owner, repo, issueNumber, label)
+	m.observe("RemoveLabelFromIssue", start, err)
+	return err
+}
+
+func (m *MetricsService) CreateIssueComment(ctx context.Context, owner, repo string, issueNumber int, comment string) error {
+	start := time.Now()
+	err := m.service.CreateIssueComment(ctx, owner, repo, issueNumber, comment)
+	m.observe("CreateIssueComment", start, err)
+	return err
+}
+
+func (m *MetricsService) SearchIssues(ctx context.Context, owner, repo, query string, limit int) ([]Issue, error) {
+	start := time.Now()
+	issues, err := m.service.SearchIssues(ctx, owner, repo, query, limit)
+	m.observe("SearchIssues", start, err)
+	return issues, err
+}
+
+func (m *MetricsService) GetUser(ctx context.Context, login string) (*User, error) {
+	start := time.Now()
+	user, err := m.service.GetUser(ctx, login)
+	m.observe("GetUser", start, err)
+	return user, err
+}
+
+func (m *MetricsService) AddAssignees(ctx context.Context, owner, repo string, issueNumber int, assignees []string) error {
+	start := time.Now()
+	err := m.service.AddAssignees(ctx, owner, repo, issueNumber, assignees)
+	m.observe("AddAssignees", start, err)
+	return err
+}
+
+func (m *MetricsService) GetOpenMilestones(ctx context.Context, owner, repo string) ([]Milestone, error) {
+	start := time.Now()
+	milestones, err := m.service.GetOpenMilestones(ctx, owner, repo)
+	m.observe("GetOpenMilestones", start, err)
+	return milestones, err
+}
+
+func (m *MetricsService) SetIssueMilestone(ctx context.Context, owner, repo string, issueNumber, milestoneNumber int) error {
+	start := time.Now()
+	err := m.service.SetIssueMilestone(ctx, owner, repo, issueNumber, milestoneNumber)
+	m.observe("SetIssueMilestone", start, err)
+	return err
+}
+
+func (m *MetricsService) GetProject(ctx context.Context, owner string, number int) (*Project, error) {
+	start := time.Now()
+	project, err := m.service.GetProject(ctx, owner, number)
+	m.observe("GetProject", start, err)
+	return project, err
+}
+
+func (m *MetricsService) AddIssueToProject(ctx context.Context, projectID, issueNodeID string) (string, error) {
+	start := time.Now()
+	itemID, err := m.service.AddIssueToProject(ctx, projectID, issueNodeID)
+	m.observe("AddIssueToProject", start, err)
+	return itemID, err
+}
+
+func (m *MetricsService) UpdateProjectItemField(ctx context.Context, projectID, itemID, fieldID string, value ProjectFieldValue) error {
+	start := time.Now()
+	err := m.service.UpdateProjectItemField(ctx, projectID, itemID, fieldID, value)
+	m.observe("UpdateProjectItemField", start, err)
+	return err
+}
@@ -0,0 +1,38 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// GitHubService is the part of the GitHub API used by the issue assistant: repository
+// content, labels, comments, issue search and updates, milestones, projects and users.
+// Client implements it; the decorators in this package wrap any implementation.
+type GitHubService interface {
+	GetRepositoryContent(ctx context.Context, owner, repo string) ([]GitHubFile, error)
+	GetRepositoryTree(ctx context.Context, owner, repo string) ([]string, error)
+	GetDirectoryFiles(ctx context.Context, owner, repo, path string) ([]GitHubFile, error)
+	GetFile(ctx context.Context, owner, repo, path string) (string, bool, error)
+	GetCommitAuthors(ctx context.Context, owner, repo, path string, since time.Time, limit int) ([]string, error)
+
+	GetLabelsForAIAnalysis(ctx context.Context, owner, repo string) (string, []*github.Label, error)
+	AddLabelsToIssue(ctx context.Context, owner, repo string, issueNumber int, labels []string) error
+	RemoveLabelFromIssue(ctx context.Context, owner, repo string, issueNumber int, label string) error
+
+	CreateIssueComment(ctx context.Context, owner, repo string, issueNumber int, comment string) error
+	SearchIssues(ctx context.Context, owner, repo, query string, limit int) ([]Issue, error)
+	AddAssignees(ctx context.Context, owner, repo string, issueNumber int, assignees []string) error
+
+	GetOpenMilestones(ctx context.Context, owner, repo string) ([]Milestone, error)
+	SetIssueMilestone(ctx context.Context, owner, repo string, issueNumber, milestoneNumber int) error
+
+	GetProject(ctx context.Context, owner string, number int) (*Project, error)
+	AddIssueToProject(ctx context.Context, projectID, issueNodeID string) (string, error)
+	UpdateProjectItemField(ctx context.Context, projectID, itemID, fieldID string, value ProjectFieldValue) error
+
+	GetUser(ctx context.Context, login string) (*User, error)
+}
+
+var _ GitHubService = (*Client)(nil)
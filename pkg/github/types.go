@@ -1,5 +1,7 @@
 package github
 
+import "time"
+
 type GitHubFile struct {
 	Path    string
 	Content string
@@ -13,6 +15,106 @@ type LabelAnalysis struct {
 	Explanation string
 }
 
+// Issue represents an existing GitHub issue
+type Issue struct {
+	Number   int
+	Title    string
+	Body     string
+	State    string
+	URL      string
+	ClosedAt *time.Time
+}
+
+// User is a GitHub account
+type User struct {
+	Login string
+	// Type is "User", "Organization" or "Bot"
+	Type      string
+	CreatedAt time.Time
+}
+
+// DuplicateCandidate is an existing issue that may be a duplicate of the analyzed one
+type DuplicateCandidate struct {
+	Issue Issue
+	// Similarity is the local text similarity score (0.0-1.0)
+	Similarity float64
+}
+
+// DuplicateMatch is a candidate the AI considers a likely duplicate
+type DuplicateMatch struct {
+	Number     int     `json:"number"`
+	Confidence float64 `json:"confidence" schema:"min=0,max=1"`
+	Reason     string  `json:"reason"`
+}
+
+// DuplicateAnalysis represents the result of checking an issue against duplicate candidates
+type DuplicateAnalysis struct {
+	// Duplicates are the candidates confirmed as likely duplicates
+	Duplicates []DuplicateMatch `json:"duplicates"`
+	// Explanation provides reasoning for the decision
+	Explanation string `json:"explanation"`
+}
+
+// TriageAnalysis represents the structured triage result of an issue
+type TriageAnalysis struct {
+	// Type is the kind of issue: bug, feature, question or docs
+	Type string `json:"type"`
+	// Severity is the impact of the issue: critical, high, medium or low
+	Severity string `json:"severity"`
+	// Priority is the suggested urgency: p0 (highest) to p3 (lowest)
+	Priority string `json:"priority"`
+	// Component is the affected repository path, empty when unknown
+	Component string `json:"component"`
+	// Reproducibility is how reliably the issue occurs: always, intermittent, unknown or not-applicable
+	Reproducibility string `json:"reproducibility"`
+	// Summary is a one or two sentence summary of the issue
+	Summary string `json:"summary"`
+}
+
+// Milestone represents a repository milestone
+type Milestone struct {
+	Number int
+	Title  string
+}
+
+// Project represents a GitHub Projects (v2) board
+type Project struct {
+	ID     string
+	Title  string
+	Fields []ProjectField
+}
+
+// ProjectField is a single select or iteration field of a project
+type ProjectField struct {
+	ID   string
+	Name string
+	// Options are the choices of a single select field
+	Options []ProjectFieldOption
+	// Iterations are the active and upcoming iterations of an iteration field, by start date
+	Iterations []ProjectIteration
+}
+
+// ProjectFieldOption is a choice of a single select field
+type ProjectFieldOption struct {
+	ID   string
+	Name string
+}
+
+// ProjectIteration is an iteration of an iteration field
+type ProjectIteration struct {
+	ID        string
+	Title     string
+	StartDate time.Time
+	// Duration is the length of the iteration in days
+	Duration int
+}
+
+// ProjectFieldValue is the value set on a project item field, exactly one member must be set
+type ProjectFieldValue struct {
+	SingleSelectOptionID string
+	IterationID          string
+}
+
 // FileFilter represents the configuration for file filtering
 type FileFilter struct {
 	// AllowedExtensions is a list of file extensions to include (e.g. ".go", ".md")
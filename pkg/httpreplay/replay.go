@@ -0,0 +1,225 @@
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Transport talks to the real API or to a cassette
+type Mode int
+
+const (
+	// Replay serves responses from the cassette and fails on unknown requests
+	Replay Mode = iota
+	// Record sends requests to the real API and stores the interactions in the cassette
+	Record
+)
+
+// ErrNoInteraction is returned in replay mode for a request the cassette has no response for
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+// Interaction is a recorded request and its response. Request headers are not
+// recorded so API keys never end up in cassettes.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies a request by method, URL and body
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response served in replay mode
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Transport records HTTP interactions to a cassette file or replays them from it,
+// so provider clients can run offline against previously recorded responses
+type Transport struct {
+	mode Mode
+	path string
+	base http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New creates a transport for the cassette at path. In replay mode the cassette is
+// loaded, in record mode requests are sent with base (http.DefaultTransport when nil).
+func New(path string, mode Mode, base http.RoundTripper) (*Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{mode: mode, path: path, base: base}
+
+	if mode == Replay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &t.interactions); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		t.used = make([]bool, len(t.interactions))
+	}
+
+	return t, nil
+}
+
+// Client returns an HTTP client using the transport
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// RoundTrip records or replays a request
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.mode == Replay {
+		return t.replay(req, recorded)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	t.interactions = append(t.interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       string(body),
+		},
+	})
+	t.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay serves the first unused interaction matching the request, so identical
+// requests get their responses in recorded order
+func (t *Transport) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.interactions {
+		if t.used[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		t.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w for %s %s", ErrNoInteraction, recorded.Method, recorded.URL)
+}
+
+// Unused returns the recorded requests that were not replayed, useful to detect
+// requests a test expected but the code no longer makes
+func (t *Transport) Unused() []RecordedRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var unused []RecordedRequest
+	for i, interaction := range t.interactions {
+		if !t.used[i] {
+			unused = append(unused, interaction.Request)
+		}
+	}
+	return unused
+}
+
+// Save writes the recorded interactions to the cassette. It does nothing in replay mode.
+func (t *Transport) Save() error {
+	if t.mode != Record {
+		return nil
+	}
+
+	t.mu.Lock()
+	data, err := json.MarshalIndent(t.interactions, "", "  ")
+	t.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(t.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// recordRequest reads the request body, leaving it readable for the real transport
+func recordRequest(req *http.Request) (RecordedRequest, error) {
+	recorded := RecordedRequest{Method: req.Method, URL: req.URL.String()}
+	if req.Body == nil {
+		return recorded, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return RecordedRequest{}, fmt.Errorf("failed to read request: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	recorded.Body = string(body)
+	return recorded, nil
+}
+
+// matches compares requests, ignoring formatting differences of JSON bodies
+func matches(a, b RecordedRequest) bool {
+	if a.Method != b.Method || a.URL != b.URL {
+		return false
+	}
+	return canonical(a.Body) == canonical(b.Body)
+}
+
+func canonical(body string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return body
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return string(normalized)
+}
@@ -0,0 +1,131 @@
+package httpreplay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	var served atomic.Int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := served.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Request-Id", fmt.Sprintf("req-%d", n))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"n":%d,"echo":%s}`, n, body)
+	}))
+	defer api.Close()
+
+	cassette := filepath.Join(t.TempDir(), "cassettes", "api.json")
+
+	recorder, err := New(cassette, Record, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := []string{
+		send(t, recorder.Client(), api.URL+"/v1/items", `{"name":"a"}`),
+		send(t, recorder.Client(), api.URL+"/v1/items", `{"name":"a"}`),
+		send(t, recorder.Client(), api.URL+"/v1/items", `{"name":"b"}`),
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-key") {
+		t.Error("cassette contains the request headers")
+	}
+
+	replayer, err := New(cassette, Replay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Identical requests are replayed in recorded order, JSON bodies match regardless of formatting
+	replayed := []string{
+		send(t, replayer.Client(), api.URL+"/v1/items", `{"name": "a"}`),
+		send(t, replayer.Client(), api.URL+"/v1/items", `{ "name":"a" }`),
+	}
+	for i, body := range replayed {
+		if body != recorded[i] {
+			t.Errorf("replayed response %d = %s, want %s", i, body, recorded[i])
+		}
+	}
+	if served.Load() != 3 {
+		t.Errorf("server served %d requests, want 3 recorded and none replayed", served.Load())
+	}
+
+	unused := replayer.Unused()
+	if len(unused) != 1 || unused[0].Body != `{"name":"b"}` {
+		t.Errorf("unused = %+v, want the request for b", unused)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, api.URL+"/v1/items", strings.NewReader(`{"name":"c"}`))
+	if _, err := replayer.RoundTrip(req); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("unknown request: got %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestReplayResponse(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "api.json")
+	err := os.WriteFile(cassette, []byte(`[{
+		"request": {"method": "GET", "url": "https://api.example.com/v1/models"},
+		"response": {"status_code": 429, "header": {"Retry-After": ["2"]}, "body": "{\"error\":\"rate limited\"}"}
+	}]`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := New(cassette, Replay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := replayer.Client().Get("https://api.example.com/v1/models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "2" || string(body) != `{"error":"rate limited"}` {
+		t.Errorf("replayed %d %v %s, want the recorded rate limit response", resp.StatusCode, resp.Header, body)
+	}
+
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), Replay, nil); err == nil {
+		t.Error("missing cassette was accepted")
+	}
+}
+
+// send posts body to url with an API key header and returns the response body
+func send(t *testing.T, client *http.Client, url, body string) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret-key")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,92 @@
+package injection
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Finding is a passage of text that looks like an attempt to instruct the AI
+type Finding struct {
+	// Rule names the kind of attempt, e.g. "override-instructions"
+	Rule string
+	// Match is the matching passage
+	Match string
+}
+
+type rule struct {
+	name    string
+	pattern *regexp.Regexp
+	// except matches the text following a match that makes it ordinary prose,
+	// e.g. "ignore the previous instructions in the README"
+	except *regexp.Regexp
+}
+
+// rules match phrases typical of prompt injection. They favor precision: ordinary
+// bug reports rarely address the model, its instructions or its output.
+var rules = []rule{
+	{
+		name:    "override-instructions",
+		pattern: regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override|bypass)\s+(?:all\s+|any\s+|the\s+|these\s+|those\s+)*(?:(?:your|previous|prior|above|earlier|preceding|system|original)\s+)+(?:instructions|prompts?|rules|directions|guidelines|context)\b`),
+		except:  regexp.MustCompile(`(?i)^\s+(?:in|from|of|on|at|under)\s+(?:the\s+|this\s+|that\s+|our\s+)?(?:readme|docs?|documentation|wiki|guide|manual|tutorial|changelog|release\s+notes|install(?:ation|er)?|setup|page|section|file|step|comment|issue|thread|pull\s+request|pr)\b`),
+	},
+	{
+		name:    "new-instructions",
+		pattern: regexp.MustCompile(`(?i)\b(?:(?:new|updated|real|actual)\s+system\s+instructions|(?:real|actual)\s+instructions|new\s+instructions\s+for\s+(?:the\s+)?(?:ai|assistant|model|bot))\s*:`),
+	},
+	{
+		name:    "role-change",
+		pattern: regexp.MustCompile(`(?i)\b(?:you\s+are\s+now\s+(?:an?\s+|the\s+)?(?:ai|assistant|model|system|admin|maintainer|unrestricted|in\s+\w+\s+mode)|act\s+as\s+(?:an?\s+|the\s+)?(?:ai|assistant|chatbot)|pretend\s+(?:to\s+be|you\s+are)\s+(?:an?\s+|the\s+)?(?:ai|assistant|model|system|admin|maintainer|unrestricted))\b`),
+	},
+	{
+		name:    "system-prompt",
+		pattern: regexp.MustCompile(`(?i)\b(?:(?:reveal|print|show|repeat|output)\s+your\s+(?:system\s+prompt|instructions|prompt)|reveal\s+the\s+system\s+prompt)\b`),
+	},
+	{
+		name:    "chat-markup",
+		pattern: regexp.MustCompile(`(?i)<\|(?:im_start|im_end|system|endoftext)\|>|\[/?INST\]|<</?SYS>>`),
+	},
+	{
+		// <file> alone is common in XML, the prompt opens files with a path attribute
+		name:    "delimiter-spoofing",
+		pattern: regexp.MustCompile(`(?i)</?\s*(?:issue_title|issue_body)\s*>|<\s*file\s+path\s*=`),
+	},
+	{
+		name:    "steer-labels",
+		pattern: regexp.MustCompile("(?i)\\b(?:add|apply|set|assign)\\s+(?:the\\s+)?(?:label|labels|tag)s?\\s+[`\"']?[\\w:/-]+[`\"']?\\s+(?:to\\s+this\\s+issue|automatically|immediately)\\b"),
+		// "add the label bug automatically when ..." describes a feature
+		except: regexp.MustCompile(`(?i)^\s+(?:when|whenever|if|for|based|after|once|on)\b`),
+	},
+}
+
+// Scan returns the passages of the texts that look like prompt injection, at most one per rule
+func Scan(texts ...string) []Finding {
+	var findings []Finding
+	for _, rule := range rules {
+		for _, text := range texts {
+			if match := rule.find(text); match != "" {
+				findings = append(findings, Finding{Rule: rule.name, Match: strings.TrimSpace(match)})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// find returns the first match of the rule in text that is not an exception, or ""
+func (r rule) find(text string) string {
+	for _, loc := range r.pattern.FindAllStringIndex(text, -1) {
+		if r.except == nil || !r.except.MatchString(text[loc[1]:]) {
+			return text[loc[0]:loc[1]]
+		}
+	}
+	return ""
+}
+
+// Rules returns the names of the rules of the findings
+func Rules(findings []Finding) []string {
+	names := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		names = append(names, finding.Rule)
+	}
+	return names
+}
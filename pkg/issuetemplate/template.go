@@ -0,0 +1,325 @@
+package issuetemplate
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// noResponse is the text GitHub fills in for issue form fields left empty
+const noResponse = "_No response_"
+
+// Template represents a parsed issue template, either markdown or issue form
+type Template struct {
+	// Path is the location of the template in the repository
+	Path string
+	// Name is the display name of the template
+	Name string
+	// Title is the default issue title (e.g. "[Bug]: ")
+	Title string
+	// Labels are the labels the template applies to new issues
+	Labels []string
+	// Sections are the headings or form fields the template asks for
+	Sections []Section
+}
+
+// Section is a single heading of a markdown template or field of an issue form
+type Section struct {
+	// Heading is the section heading, which is also the heading issue forms render
+	Heading string
+	// Required reports whether the section must be filled in
+	Required bool
+	// Placeholder is the prefilled text that does not count as an answer
+	Placeholder string
+}
+
+// frontMatter is the YAML header shared by markdown templates and issue forms
+type frontMatter struct {
+	Name   string       `yaml:"name"`
+	Title  string       `yaml:"title"`
+	Labels stringOrList `yaml:"labels"`
+}
+
+// issueForm is the subset of the issue forms schema needed to find required fields
+type issueForm struct {
+	frontMatter `yaml:",inline"`
+	Body        []struct {
+		Type       string `yaml:"type"`
+		Attributes struct {
+			Label       string `yaml:"label"`
+			Placeholder string `yaml:"placeholder"`
+			Value       string `yaml:"value"`
+		} `yaml:"attributes"`
+		Validations struct {
+			Required bool `yaml:"required"`
+		} `yaml:"validations"`
+	} `yaml:"body"`
+}
+
+// stringOrList decodes labels given either as a comma separated string or a list
+type stringOrList []string
+
+func (s *stringOrList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.SequenceNode {
+		var list []string
+		if err := value.Decode(&list); err != nil {
+			return err
+		}
+		*s = list
+		return nil
+	}
+
+	var single string
+	if err := value.Decode(&single); err != nil {
+		return err
+	}
+	for _, label := range strings.Split(single, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			*s = append(*s, label)
+		}
+	}
+	return nil
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+	commentPattern = regexp.MustCompile(`(?s)<!--(.*?)-->`)
+	fencePattern   = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+)
+
+// Parse parses an issue template file, returning false for files that are not templates (e.g. config.yml)
+func Parse(path, content string) (Template, bool, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md":
+		template, err := ParseMarkdown(path, content)
+		return template, err == nil, err
+	case ".yml", ".yaml":
+		if strings.EqualFold(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), "config") {
+			return Template{}, false, nil
+		}
+		template, err := ParseForm(path, content)
+		return template, err == nil, err
+	default:
+		return Template{}, false, nil
+	}
+}
+
+// ParseMarkdown parses a markdown issue template. Every heading is a required section
+// unless it is marked as optional, in the heading (e.g. "Logs (optional)") or in an
+// HTML comment starting the text below it (e.g. "<!-- Optional: ... -->").
+func ParseMarkdown(path, content string) (Template, error) {
+	template := Template{Path: path}
+
+	body := content
+	if header, rest, ok := splitFrontMatter(content); ok {
+		var fm frontMatter
+		if err := yaml.Unmarshal([]byte(header), &fm); err != nil {
+			return Template{}, fmt.Errorf("failed to parse front matter of %s: %w", path, err)
+		}
+		template.Name = fm.Name
+		template.Title = fm.Title
+		template.Labels = fm.Labels
+		body = rest
+	}
+
+	for _, section := range splitSections(body) {
+		template.Sections = append(template.Sections, Section{
+			Heading:     section.heading,
+			Required:    !isOptional(section),
+			Placeholder: section.content,
+		})
+	}
+
+	return template, nil
+}
+
+// ParseForm parses an issue forms YAML template
+func ParseForm(path, content string) (Template, error) {
+	var form issueForm
+	if err := yaml.Unmarshal([]byte(content), &form); err != nil {
+		return Template{}, fmt.Errorf("failed to parse issue form %s: %w", path, err)
+	}
+
+	template := Template{
+		Path:   path,
+		Name:   form.Name,
+		Title:  form.Title,
+		Labels: form.Labels,
+	}
+
+	for _, field := range form.Body {
+		if field.Type == "markdown" || field.Attributes.Label == "" {
+			continue
+		}
+		placeholder := field.Attributes.Value
+		if placeholder == "" {
+			placeholder = field.Attributes.Placeholder
+		}
+		template.Sections = append(template.Sections, Section{
+			Heading:     field.Attributes.Label,
+			Required:    field.Validations.Required,
+			Placeholder: placeholder,
+		})
+	}
+
+	return template, nil
+}
+
+// Missing returns the required sections of the template that the issue body omits
+// or leaves empty, unchanged from the template or as a placeholder
+func (t Template) Missing(body string) []Section {
+	answers := make(map[string]string)
+	for _, section := range splitSections(body) {
+		answers[normalize(section.heading)] = section.content
+	}
+
+	var missing []Section
+	for _, section := range t.Sections {
+		if !section.Required {
+			continue
+		}
+		answer, ok := answers[normalize(section.Heading)]
+		if !ok || isPlaceholder(answer, section.Placeholder) {
+			missing = append(missing, section)
+		}
+	}
+	return missing
+}
+
+// Match selects the template an issue was most likely created from. Templates whose
+// labels the issue carries or whose title prefix it uses win, otherwise the template
+// sharing the most headings with the body is chosen.
+func Match(templates []Template, title string, labels []string, body string) (Template, bool) {
+	issueLabels := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		issueLabels[strings.ToLower(label)] = true
+	}
+
+	headings := make(map[string]bool)
+	for _, section := range splitSections(body) {
+		headings[normalize(section.heading)] = true
+	}
+
+	var best Template
+	bestScore := 0
+	for _, template := range templates {
+		score := 0
+		if len(template.Labels) > 0 && hasAllLabels(issueLabels, template.Labels) {
+			score += 100
+		}
+		if prefix := strings.TrimSpace(template.Title); prefix != "" && strings.HasPrefix(strings.ToLower(title), strings.ToLower(prefix)) {
+			score += 100
+		}
+		for _, section := range template.Sections {
+			if headings[normalize(section.Heading)] {
+				score++
+			}
+		}
+
+		if score > bestScore {
+			best = template
+			bestScore = score
+		}
+	}
+
+	return best, bestScore > 0
+}
+
+type rawSection struct {
+	heading string
+	content string
+}
+
+// isOptional reports whether a markdown template marks a section as optional
+func isOptional(section rawSection) bool {
+	if strings.Contains(strings.ToLower(section.heading), "optional") {
+		return true
+	}
+	match := commentPattern.FindStringSubmatch(section.content)
+	return match != nil && strings.HasPrefix(section.content, match[0]) &&
+		strings.HasPrefix(strings.ToLower(strings.TrimSpace(match[1])), "optional")
+}
+
+// splitSections splits markdown into headings and the text below each of them.
+// Headings inside fenced code blocks, opened with ``` or ~~~, are content.
+func splitSections(markdown string) []rawSection {
+	var sections []rawSection
+	var content []string
+	// fence is the opening fence of the current code block, empty outside of code blocks
+	fence := ""
+
+	flush := func() {
+		if len(sections) > 0 {
+			sections[len(sections)-1].content = strings.TrimSpace(strings.Join(content, "\n"))
+		}
+		content = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		if match := fencePattern.FindStringSubmatch(line); match != nil {
+			switch {
+			case fence == "":
+				fence = match[1]
+			case match[1][0] == fence[0] && len(match[1]) >= len(fence) && strings.TrimSpace(line[len(match[0]):]) == "":
+				// A closing fence uses the character of the opening fence, at least as often, without info string
+				fence = ""
+				content = append(content, line)
+				continue
+			}
+		}
+		if fence == "" {
+			if match := headingPattern.FindStringSubmatch(line); match != nil {
+				flush()
+				sections = append(sections, rawSection{heading: match[1]})
+				continue
+			}
+		}
+		content = append(content, line)
+	}
+	flush()
+
+	return sections
+}
+
+// splitFrontMatter separates the YAML front matter from the markdown body. The front
+// matter is enclosed in lines of exactly "---", a longer line such as a "----" rule is
+// part of it.
+func splitFrontMatter(content string) (string, string, bool) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+	if strings.TrimRight(lines[0], " \t") != "---" {
+		return "", content, false
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t") == "---" {
+			return strings.Join(lines[1:i], "\n"), strings.Join(lines[i+1:], "\n"), true
+		}
+	}
+	return "", content, false
+}
+
+// isPlaceholder reports whether an answer carries no information beyond the template
+func isPlaceholder(answer, placeholder string) bool {
+	answer = normalize(commentPattern.ReplaceAllString(answer, ""))
+	if answer == "" || answer == normalize(noResponse) {
+		return true
+	}
+	placeholder = normalize(commentPattern.ReplaceAllString(placeholder, ""))
+	return placeholder != "" && answer == placeholder
+}
+
+func hasAllLabels(issueLabels map[string]bool, labels []string) bool {
+	for _, label := range labels {
+		if !issueLabels[strings.ToLower(label)] {
+			return false
+		}
+	}
+	return true
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
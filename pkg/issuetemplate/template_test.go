@@ -0,0 +1,324 @@
+package issuetemplate
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const bugReport = `---
+name: Bug report
+about: Something isn't working
+title: "[Bug]: "
+labels: bug, needs-triage
+---
+## Steps to reproduce
+<!-- Describe how to trigger the bug -->
+
+## Expected behavior
+Tell us what should happen
+
+## Logs (optional)
+
+## Screenshots
+<!-- Optional: attach screenshots -->
+
+## Version
+`
+
+const featureForm = `name: Feature request
+description: Suggest an idea
+title: "[Feature]: "
+labels:
+  - enhancement
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for taking the time to suggest a feature!
+  - type: textarea
+    attributes:
+      label: Problem
+      placeholder: What problem does the feature solve?
+    validations:
+      required: true
+  - type: input
+    attributes:
+      label: Alternatives
+  - type: dropdown
+    attributes:
+      label: Priority
+      value: Medium
+    validations:
+      required: true
+`
+
+// headings returns the headings of sections and whether each is required
+func headings(sections []Section) []string {
+	var names []string
+	for _, section := range sections {
+		name := section.Heading
+		if section.Required {
+			name += "*"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestParseMarkdown(t *testing.T) {
+	template, err := ParseMarkdown(".github/ISSUE_TEMPLATE/bug.md", bugReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if template.Name != "Bug report" || template.Title != "[Bug]: " {
+		t.Errorf("name %q and title %q, want the front matter", template.Name, template.Title)
+	}
+	if want := []string{"bug", "needs-triage"}; !slices.Equal(template.Labels, want) {
+		t.Errorf("labels = %v, want %v", template.Labels, want)
+	}
+	// Sections are optional by their heading or a leading comment
+	want := []string{"Steps to reproduce*", "Expected behavior*", "Logs (optional)", "Screenshots", "Version*"}
+	if got := headings(template.Sections); !slices.Equal(got, want) {
+		t.Errorf("sections = %v, want %v", got, want)
+	}
+	if got := template.Sections[1].Placeholder; got != "Tell us what should happen" {
+		t.Errorf("placeholder = %q", got)
+	}
+}
+
+func TestParseMarkdownFrontMatter(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantName     string
+		wantSections []string
+	}{
+		{
+			name:         "no front matter",
+			content:      "## Summary\n\n## Version\n",
+			wantSections: []string{"Summary*", "Version*"},
+		},
+		{
+			name:         "windows line endings",
+			content:      "---\r\nname: Bug\r\n---\r\n## Summary\r\n",
+			wantName:     "Bug",
+			wantSections: []string{"Summary*"},
+		},
+		{
+			// A longer rule does not close the front matter
+			name:         "rule in front matter",
+			content:      "---\nname: Bug\nabout: |\n  Report a bug\n  ----\n  Thanks!\n---\n## Summary\n",
+			wantName:     "Bug",
+			wantSections: []string{"Summary*"},
+		},
+		{
+			name:         "rule below the front matter",
+			content:      "---\nname: Bug\n---\n----\n## Summary\n",
+			wantName:     "Bug",
+			wantSections: []string{"Summary*"},
+		},
+		{
+			name:         "unclosed front matter",
+			content:      "---\n## Summary\n",
+			wantSections: []string{"Summary*"},
+		},
+		{
+			name:         "closing delimiter with trailing spaces",
+			content:      "--- \nname: Bug\n---  \n## Summary\n",
+			wantName:     "Bug",
+			wantSections: []string{"Summary*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := ParseMarkdown("bug.md", tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if template.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", template.Name, tt.wantName)
+			}
+			if got := headings(template.Sections); !slices.Equal(got, tt.wantSections) {
+				t.Errorf("sections = %v, want %v", got, tt.wantSections)
+			}
+		})
+	}
+}
+
+func TestSplitSectionsCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string
+	}{
+		{"backtick fence", "## Logs\n```\n# not a heading\n```\n## Version\n", []string{"Logs", "Version"}},
+		{"tilde fence", "## Logs\n~~~\n# not a heading\n~~~\n## Version\n", []string{"Logs", "Version"}},
+		{"fence with info string", "## Logs\n```shell\n# comment\n```\n## Version\n", []string{"Logs", "Version"}},
+		{"tilde inside backticks", "## Logs\n```\n~~~\n# not a heading\n```\n## Version\n", []string{"Logs", "Version"}},
+		{"longer closing fence", "## Logs\n````\n```\n# not a heading\n`````\n## Version\n", []string{"Logs", "Version"}},
+		{"unclosed fence", "## Logs\n```\n# not a heading\n## Version\n", []string{"Logs"}},
+		{"closing heading hashes", "## Version ##\nv1\n", []string{"Version"}},
+		{"hashtag is not a heading", "## Summary\n#123 crashes\n", []string{"Summary"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, section := range splitSections(tt.markdown) {
+				got = append(got, section.heading)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("headings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseForm(t *testing.T) {
+	template, err := ParseForm(".github/ISSUE_TEMPLATE/feature.yml", featureForm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if template.Name != "Feature request" || template.Title != "[Feature]: " {
+		t.Errorf("name %q and title %q", template.Name, template.Title)
+	}
+	if want := []string{"enhancement"}; !slices.Equal(template.Labels, want) {
+		t.Errorf("labels = %v, want %v", template.Labels, want)
+	}
+	// Markdown blocks are not fields
+	want := []string{"Problem*", "Alternatives", "Priority*"}
+	if got := headings(template.Sections); !slices.Equal(got, want) {
+		t.Errorf("sections = %v, want %v", got, want)
+	}
+	if got := template.Sections[2].Placeholder; got != "Medium" {
+		t.Errorf("placeholder = %q, want the default value", got)
+	}
+
+	if _, err := ParseForm("broken.yml", "body: [\n"); err == nil {
+		t.Error("invalid YAML was accepted")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		path   string
+		wantOK bool
+	}{
+		{".github/ISSUE_TEMPLATE/bug.md", true},
+		{".github/ISSUE_TEMPLATE/BUG.MD", true},
+		{".github/ISSUE_TEMPLATE/feature.yml", true},
+		{".github/ISSUE_TEMPLATE/feature.yaml", true},
+		{".github/ISSUE_TEMPLATE/config.yml", false},
+		{".github/ISSUE_TEMPLATE/README.txt", false},
+	}
+
+	for _, tt := range tests {
+		content := bugReport
+		if !strings.HasSuffix(strings.ToLower(tt.path), ".md") {
+			content = featureForm
+		}
+		if _, ok, err := Parse(tt.path, content); ok != tt.wantOK || err != nil {
+			t.Errorf("Parse(%s) = %v, %v, want %v", tt.path, ok, err, tt.wantOK)
+		}
+	}
+}
+
+func TestMissing(t *testing.T) {
+	bug, err := ParseMarkdown("bug.md", bugReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feature, err := ParseForm("feature.yml", featureForm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		template Template
+		body     string
+		want     []string
+	}{
+		{
+			name:     "complete",
+			template: bug,
+			body:     "## Steps to reproduce\nRun it.\n\n## Expected behavior\nNo crash.\n\n## Version\nv1.2.0\n",
+		},
+		{
+			name:     "omitted and empty sections",
+			template: bug,
+			body:     "## Steps to reproduce\nRun it.\n\n## Version\n",
+			want:     []string{"Expected behavior*", "Version*"},
+		},
+		{
+			name:     "template comments and placeholders are no answer",
+			template: bug,
+			body:     "## Steps to reproduce\n<!-- Describe how to trigger the bug -->\n\n## Expected behavior\nTell us what should  happen\n\n## Version\n1.0\n",
+			want:     []string{"Steps to reproduce*", "Expected behavior*"},
+		},
+		{
+			name:     "headings match regardless of case and spacing",
+			template: bug,
+			body:     "### steps to  REPRODUCE\nRun it.\n## Expected Behavior\nNo crash.\n# version\n2.0",
+		},
+		{
+			name:     "heading in a code block is no answer",
+			template: bug,
+			body:     "## Steps to reproduce\n~~~\n## Version\n~~~\n## Expected behavior\nNo crash.\n",
+			want:     []string{"Version*"},
+		},
+		{
+			name:     "form fields left empty",
+			template: feature,
+			body:     "### Problem\n\n_No response_\n\n### Alternatives\n\n_No response_\n\n### Priority\n\nMedium\n",
+			want:     []string{"Problem*", "Priority*"},
+		},
+		{
+			name:     "form fields answered",
+			template: feature,
+			body:     "### Problem\n\nExports are slow.\n\n### Alternatives\n\n_No response_\n\n### Priority\n\nHigh\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := headings(tt.template.Missing(tt.body)); !slices.Equal(got, tt.want) {
+				t.Errorf("missing = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	bug, _ := ParseMarkdown("bug.md", bugReport)
+	feature, _ := ParseForm("feature.yml", featureForm)
+	question, _ := ParseMarkdown("question.md", "## Question\n\n## Context\n")
+	templates := []Template{bug, feature, question}
+
+	tests := []struct {
+		name   string
+		title  string
+		labels []string
+		body   string
+		want   string
+	}{
+		{"labels", "Crash", []string{"Bug", "needs-triage"}, "", "bug.md"},
+		{"some labels are not enough", "Crash", []string{"bug"}, "### Problem\nslow", "feature.yml"},
+		{"title prefix", "[feature]: dark mode", nil, "", "feature.yml"},
+		{"headings", "How do I configure it?", nil, "## Question\nHow?\n## Context\nv1", "question.md"},
+		{"labels win over headings", "Crash", []string{"enhancement"}, "## Question\n## Context\n", "feature.yml"},
+		{"no match", "Hello", nil, "Just some text", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Match(templates, tt.title, tt.labels, tt.body)
+			if ok != (tt.want != "") || got.Path != tt.want {
+				t.Errorf("Match = %q, %v, want %q", got.Path, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/workflowkit/issue-assistant/pkg/actions"
+)
+
+// actionsLogger writes GitHub Actions workflow commands: errors and warnings become
+// annotations, debug messages are shown when step debug logging is enabled, and
+// the errors and warnings of the run are added to the job summary on Close
+type actionsLogger struct {
+	*actionsOutput
+	level  Level
+	fields []Field
+	redact func(string) string
+}
+
+// actionsOutput is shared by an actions logger and the loggers derived from it with With
+type actionsOutput struct {
+	mu       sync.Mutex
+	out      io.Writer
+	problems []string
+}
+
+func setupActionsLogger(o options) *actionsLogger {
+	return &actionsLogger{actionsOutput: &actionsOutput{out: os.Stdout}, level: o.level, redact: o.redact}
+}
+
+func (l *actionsLogger) Error(msg string) {
+	if l.level > ErrorLevel {
+		return
+	}
+	msg = l.redact(formatFields(msg, l.fields))
+	l.command("error", msg)
+	l.record("❌ " + msg)
+}
+
+func (l *actionsLogger) Errorf(msg string, args ...interface{}) {
+	l.Error(fmt.Sprintf(msg, args...))
+}
+
+func (l *actionsLogger) Warn(msg string) {
+	if l.level > WarnLevel {
+		return
+	}
+	msg = l.redact(formatFields(msg, l.fields))
+	l.command("warning", msg)
+	l.record("⚠️ " + msg)
+}
+
+func (l *actionsLogger) Warnf(msg string, args ...interface{}) {
+	l.Warn(fmt.Sprintf(msg, args...))
+}
+
+func (l *actionsLogger) Debug(msg string) {
+	if l.level > DebugLevel {
+		return
+	}
+	l.command("debug", l.redact(formatFields(msg, l.fields)))
+}
+
+func (l *actionsLogger) Debugf(msg string, args ...interface{}) {
+	l.Debug(fmt.Sprintf(msg, args...))
+}
+
+func (l *actionsLogger) Info(msg string) {
+	if l.level > InfoLevel {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, line := range strings.Split(l.redact(formatFields(msg, l.fields)), "\n") {
+		fmt.Fprintln(l.out, neutralize(line))
+	}
+}
+
+func (l *actionsLogger) Infof(msg string, args ...interface{}) {
+	l.Info(fmt.Sprintf(msg, args...))
+}
+
+func (l *actionsLogger) Fatal(msg string) {
+	msg = l.redact(formatFields(msg, l.fields))
+	l.command("error", msg)
+	l.record("❌ " + msg)
+	_ = l.Close()
+	os.Exit(1)
+}
+
+func (l *actionsLogger) Fatalf(msg string, args ...interface{}) {
+	l.Fatal(fmt.Sprintf(msg, args...))
+}
+
+func (l *actionsLogger) With(fields ...Field) Logger {
+	return &actionsLogger{
+		actionsOutput: l.actionsOutput,
+		level:         l.level,
+		fields:        append(l.fields[:len(l.fields):len(l.fields)], fields...),
+		redact:        l.redact,
+	}
+}
+
+// StartGroup starts a collapsible group in the log. The name is redacted and escaped
+// like messages since it may contain issue content.
+func (l *actionsLogger) StartGroup(name string) {
+	l.command("group", l.redact(name))
+}
+
+// EndGroup ends the current group
+func (l *actionsLogger) EndGroup() {
+	l.command("endgroup", "")
+}
+
+// Mask hides value in all later log output of the job
+func (l *actionsLogger) Mask(value string) {
+	for _, line := range strings.Split(value, "\n") {
+		if strings.TrimSpace(line) != "" {
+			l.command("add-mask", line)
+		}
+	}
+}
+
+// Close adds the errors and warnings of the run to the job summary
+func (l *actionsLogger) Close() error {
+	l.mu.Lock()
+	problems := l.problems
+	l.problems = nil
+	l.mu.Unlock()
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	var summary strings.Builder
+	summary.WriteString("### Errors and Warnings\n\n")
+	for _, problem := range problems {
+		summary.WriteString("- " + strings.ReplaceAll(problem, "\n", " ") + "\n")
+	}
+	return actions.AppendSummary(summary.String())
+}
+
+func (l *actionsOutput) command(name, msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.out, "::%s::%s\n", name, escapeData(msg))
+}
+
+func (l *actionsOutput) record(problem string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.problems = append(l.problems, problem)
+}
+
+// escapeData escapes the message of a workflow command so it stays on a single line
+func escapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// neutralize keeps plain log lines, which may contain issue content, from being
+// interpreted as workflow commands by putting a zero width space into a leading "::"
+func neutralize(line string) string {
+	if strings.HasPrefix(strings.TrimSpace(line), "::") {
+		return strings.Replace(line, "::", ":\u200b:", 1)
+	}
+	return line
+}
@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testActionsLogger returns an actions logger writing to a buffer that redacts "s3cr3t"
+func testActionsLogger(level Level) (*actionsLogger, *bytes.Buffer) {
+	var out bytes.Buffer
+	redact := func(s string) string { return strings.ReplaceAll(s, "s3cr3t", "***") }
+	return &actionsLogger{actionsOutput: &actionsOutput{out: &out}, level: level, redact: redact}, &out
+}
+
+func TestActionsLoggerCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *actionsLogger)
+		want string
+	}{
+		{
+			name: "error",
+			log:  func(l *actionsLogger) { l.Errorf("failed to label #%d", 12) },
+			want: "::error::failed to label #12\n",
+		},
+		{
+			name: "warning with fields",
+			log:  func(l *actionsLogger) { l.With(F("issue", 12), F("feature", "labels")).Warn("slow") },
+			want: "::warning::slow issue=12 feature=labels\n",
+		},
+		{
+			name: "debug",
+			log:  func(l *actionsLogger) { l.Debug("prompt labels@1") },
+			want: "::debug::prompt labels@1\n",
+		},
+		{
+			name: "multi-line message",
+			log:  func(l *actionsLogger) { l.Error("first\r\nsecond 100%") },
+			want: "::error::first%0D%0Asecond 100%25\n",
+		},
+		{
+			name: "injected command in message",
+			log:  func(l *actionsLogger) { l.Warn("title\n::set-output name=approved::true") },
+			want: "::warning::title%0A::set-output name=approved::true\n",
+		},
+		{
+			name: "escaped newline in message",
+			log:  func(l *actionsLogger) { l.Warn("title%0A::set-output name=approved::true") },
+			want: "::warning::title%250A::set-output name=approved::true\n",
+		},
+		{
+			name: "redacted message",
+			log:  func(l *actionsLogger) { l.Error("token s3cr3t rejected") },
+			want: "::error::token *** rejected\n",
+		},
+		{
+			name: "info",
+			log:  func(l *actionsLogger) { l.Info("processing\n::add-mask::x\n  ::error::y") },
+			want: "processing\n:\u200b:add-mask::x\n  :\u200b:error::y\n",
+		},
+		{
+			name: "group",
+			log: func(l *actionsLogger) {
+				l.StartGroup("Issue #12")
+				l.EndGroup()
+			},
+			want: "::group::Issue #12\n::endgroup::\n",
+		},
+		{
+			name: "group with injected command",
+			log:  func(l *actionsLogger) { l.StartGroup("Crash\n::set-output name=approved::true") },
+			want: "::group::Crash%0A::set-output name=approved::true\n",
+		},
+		{
+			name: "group with escaped newline",
+			log:  func(l *actionsLogger) { l.StartGroup("Crash%0A::set-output name=approved::true") },
+			want: "::group::Crash%250A::set-output name=approved::true\n",
+		},
+		{
+			name: "redacted group",
+			log:  func(l *actionsLogger) { l.StartGroup("Key s3cr3t leaks") },
+			want: "::group::Key *** leaks\n",
+		},
+		{
+			name: "mask",
+			log:  func(l *actionsLogger) { l.Mask("line one\n\nline two") },
+			want: "::add-mask::line one\n::add-mask::line two\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, out := testActionsLogger(DebugLevel)
+			tt.log(l)
+			if got := out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionsLoggerLevel(t *testing.T) {
+	l, out := testActionsLogger(WarnLevel)
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+
+	if want := "::warning::warn\n::error::error\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestActionsLoggerSummary(t *testing.T) {
+	summary := t.TempDir() + "/summary.md"
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+
+	l, _ := testActionsLogger(InfoLevel)
+	l.With(F("issue", 12)).Error("failed\nto comment")
+	l.Warn("slow")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "### Errors and Warnings\n\n- ❌ failed to comment issue=12\n- ⚠️ slow\n\n"
+	if string(got) != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+}
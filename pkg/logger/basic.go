@@ -8,51 +8,69 @@ import (
 
 type basicLogger struct {
 	logger *log.Logger
+	level  Level
+	fields []Field
+	redact func(string) string
 }
 
 func (l *basicLogger) Error(msg string) {
-	l.logger.Println("❌ " + msg)
+	l.println(ErrorLevel, "❌ "+msg)
 }
 
 func (l *basicLogger) Errorf(msg string, args ...interface{}) {
 	message := fmt.Sprintf(msg, args...)
-	l.logger.Println("❌ " + message)
+	l.println(ErrorLevel, "❌ "+message)
 }
 
 func (l *basicLogger) Warn(msg string) {
-	l.logger.Println("⚠️ " + msg)
+	l.println(WarnLevel, "⚠️ "+msg)
 }
 
 func (l *basicLogger) Warnf(msg string, args ...interface{}) {
 	message := fmt.Sprintf(msg, args...)
-	l.logger.Println("⚠️ " + message)
+	l.println(WarnLevel, "⚠️ "+message)
 }
 
 func (l *basicLogger) Debug(msg string) {
-	l.logger.Println("🔍 " + msg)
+	l.println(DebugLevel, "🔍 "+msg)
 }
 
 func (l *basicLogger) Debugf(msg string, args ...interface{}) {
 	message := fmt.Sprintf(msg, args...)
-	l.logger.Println("🔍 " + message)
+	l.println(DebugLevel, "🔍 "+message)
 }
 
 func (l *basicLogger) Info(msg string) {
-	l.logger.Println("🔵 " + msg)
+	l.println(InfoLevel, "🔵 "+msg)
 }
 
 func (l *basicLogger) Infof(msg string, args ...interface{}) {
 	message := fmt.Sprintf(msg, args...)
-	l.logger.Println("🔵 " + message)
+	l.println(InfoLevel, "🔵 "+message)
 }
 
 func (l *basicLogger) Fatal(msg string) {
-	l.logger.Fatal("☠️ " + msg)
+	l.logger.Fatal(l.redact(formatFields("☠️ "+msg, l.fields)))
 }
 
 func (l *basicLogger) Fatalf(msg string, args ...interface{}) {
 	message := fmt.Sprintf(msg, args...)
-	l.logger.Fatal("☠️ " + message)
+	l.logger.Fatal(l.redact(formatFields("☠️ "+message, l.fields)))
+}
+
+func (l *basicLogger) With(fields ...Field) Logger {
+	return &basicLogger{
+		logger: l.logger,
+		level:  l.level,
+		fields: append(l.fields[:len(l.fields):len(l.fields)], fields...),
+		redact: l.redact,
+	}
+}
+
+func (l *basicLogger) println(level Level, msg string) {
+	if level >= l.level {
+		l.logger.Println(l.redact(formatFields(msg, l.fields)))
+	}
 }
 
 var (
@@ -60,10 +78,10 @@ var (
 	basicLoggerInstance *basicLogger
 )
 
-func setupBasicLogger() *basicLogger {
+func setupBasicLogger(o options) *basicLogger {
 	if basicLoggerInstance == nil {
 		onceBasic.Do(func() {
-			basicLoggerInstance = &basicLogger{logger: log.New(log.Writer(), log.Prefix(), log.Flags())}
+			basicLoggerInstance = &basicLogger{logger: log.New(log.Writer(), log.Prefix(), log.Flags()), level: o.level, redact: o.redact}
 		})
 	}
 	return basicLoggerInstance
@@ -0,0 +1,24 @@
+package logger
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the logger
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger of the context, or Log when the context carries none
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return l
+	}
+	return Log
+}
+
+// WithFields returns a context whose logger adds the fields to every line,
+// so all lines logged for a run, issue or feature can be correlated
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(fields...))
+}
@@ -1,7 +1,10 @@
 package logger
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"strings"
 )
 
 var Log Logger
@@ -14,6 +17,9 @@ const (
 
 	// ZapLogger is the zap logger
 	ZapLogger LoggerType = "zap"
+
+	// ActionsLogger writes GitHub Actions workflow commands
+	ActionsLogger LoggerType = "actions"
 )
 
 type Logger interface {
@@ -31,19 +37,194 @@ type Logger interface {
 
 	Fatal(msg string)
 	Fatalf(msg string, args ...interface{})
+
+	// With returns a logger that adds the fields to every line
+	With(fields ...Field) Logger
+}
+
+// Field is a key-value pair added to log lines, e.g. the repository or feature of a run
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F creates a log field
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Level is the minimum severity of logged lines
+type Level int8
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+// ParseLevel converts a level name (debug, info, warn or error) to a Level
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	}
+	return 0, fmt.Errorf("unknown log level: %s", s)
+}
+
+// Format is the encoding of console log lines
+type Format string
+
+const (
+	// ConsoleFormat writes human readable lines
+	ConsoleFormat Format = "console"
+
+	// JSONFormat writes a JSON object per line
+	JSONFormat Format = "json"
+)
+
+// ParseFormat converts a format name (console or json) to a Format
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case ConsoleFormat:
+		return ConsoleFormat, nil
+	case JSONFormat:
+		return JSONFormat, nil
+	}
+	return "", fmt.Errorf("unknown log format: %s", s)
+}
+
+type options struct {
+	level  Level
+	format Format
+	redact func(string) string
+}
+
+// Option configures the logger set by SetLogger
+type Option func(*options)
+
+// WithLevel sets the minimum level of logged lines
+func WithLevel(level Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithFormat sets the encoding of the zap logger, the actions logger always writes workflow commands
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// Grouper is implemented by loggers that can fold output into collapsible groups
+type Grouper interface {
+	StartGroup(name string)
+	EndGroup()
+}
+
+// Masker is implemented by loggers that can hide secrets in output
+type Masker interface {
+	Mask(value string)
+}
+
+// Closer is implemented by loggers that write a report when the run ends
+type Closer interface {
+	Close() error
+}
+
+// Detect returns the logger type for the environment, the actions logger inside GitHub Actions
+func Detect() LoggerType {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return ActionsLogger
+	}
+	return ZapLogger
+}
+
+// Group starts a collapsible group of output when the logger supports it
+// and returns the function ending it
+func Group(name string) func() {
+	grouper, ok := Log.(Grouper)
+	if !ok {
+		Log.Info(name)
+		return func() {}
+	}
+	grouper.StartGroup(name)
+	return grouper.EndGroup
+}
+
+// Mask hides a secret in all later output when the logger supports it
+func Mask(value string) {
+	if masker, ok := Log.(Masker); ok && value != "" {
+		masker.Mask(value)
+	}
+}
+
+// Close lets the logger write its report of the run
+func Close() error {
+	if closer, ok := Log.(Closer); ok {
+		return closer.Close()
+	}
+	return nil
 }
 
 func init() {
-	Log = &basicLogger{logger: log.New(log.Writer(), log.Prefix(), log.Flags())}
+	Log = &basicLogger{logger: log.New(log.Writer(), log.Prefix(), log.Flags()), redact: noRedaction}
 }
 
-// SetLogger is initialize log library
-func SetLogger(loggerInstance LoggerType) {
+// SetLogger is initialize log library. The level defaults to info, except for the
+// actions logger whose debug lines are only shown when step debug logging is enabled.
+func SetLogger(loggerInstance LoggerType, opts ...Option) {
+	o := options{level: InfoLevel, format: ConsoleFormat, redact: noRedaction}
+	if loggerInstance == ActionsLogger {
+		o.level = DebugLevel
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	if loggerInstance == ZapLogger {
-		Log = setupZapLogger()
+		Log = setupZapLogger(o)
+	} else if loggerInstance == ActionsLogger {
+		Log = setupActionsLogger(o)
 	} else if loggerInstance == BasicLogger {
-		Log = setupBasicLogger()
+		Log = setupBasicLogger(o)
 	} else {
-		Log = setupBasicLogger()
+		Log = setupBasicLogger(o)
+	}
+}
+
+// WithRedaction sets the function that removes secrets from every logged line
+func WithRedaction(redact func(string) string) Option {
+	return func(o *options) {
+		o.redact = redact
+	}
+}
+
+// noRedaction leaves log lines unchanged
+func noRedaction(s string) string {
+	return s
+}
+
+// formatFields appends the fields to a message as key=value pairs
+func formatFields(msg string, fields []Field) string {
+	if len(fields) == 0 {
+		return msg
+	}
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, field := range fields {
+		value := fmt.Sprint(field.Value)
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		b.WriteString(" " + field.Key + "=" + value)
 	}
+	return b.String()
 }
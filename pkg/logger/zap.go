@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 	"sync"
 
@@ -10,46 +11,69 @@ import (
 
 type zapLogger struct {
 	logger *zap.Logger
+	// emoji prefixes messages with the emoji of their level, only in console format
+	emoji  bool
+	redact func(string) string
 }
 
 func (l *zapLogger) Error(msg string) {
-	l.logger.Sugar().Error("❌ " + msg)
+	l.logger.Error(l.message("❌ ", msg))
 }
 
 func (l *zapLogger) Errorf(msg string, args ...interface{}) {
-	l.logger.Sugar().Errorf("❌ "+msg, args...)
+	l.logger.Error(l.message("❌ ", fmt.Sprintf(msg, args...)))
 }
 
 func (l *zapLogger) Warn(msg string) {
-	l.logger.Sugar().Warn("⚠️ " + msg)
+	l.logger.Warn(l.message("⚠️ ", msg))
 }
 
 func (l *zapLogger) Warnf(msg string, args ...interface{}) {
-	l.logger.Sugar().Warnf("⚠️ "+msg, args...)
+	l.logger.Warn(l.message("⚠️ ", fmt.Sprintf(msg, args...)))
 }
 
 func (l *zapLogger) Debug(msg string) {
-	l.logger.Sugar().Debug("🔍 " + msg)
+	l.logger.Debug(l.message("🔍 ", msg))
 }
 
 func (l *zapLogger) Debugf(msg string, args ...interface{}) {
-	l.logger.Sugar().Debugf("🔍 "+msg, args...)
+	l.logger.Debug(l.message("🔍 ", fmt.Sprintf(msg, args...)))
 }
 
 func (l *zapLogger) Info(msg string) {
-	l.logger.Sugar().Info("🔵 " + msg)
+	l.logger.Info(l.message("🔵 ", msg))
 }
 
 func (l *zapLogger) Infof(msg string, args ...interface{}) {
-	l.logger.Sugar().Infof("🔵 "+msg, args...)
+	l.logger.Info(l.message("🔵 ", fmt.Sprintf(msg, args...)))
 }
 
 func (l *zapLogger) Fatal(msg string) {
-	l.logger.Sugar().Fatal("☠️ " + msg)
+	l.logger.Fatal(l.message("☠️ ", msg))
 }
 
 func (l *zapLogger) Fatalf(msg string, args ...interface{}) {
-	l.logger.Sugar().Fatalf("☠️"+msg, args...)
+	l.logger.Fatal(l.message("☠️ ", fmt.Sprintf(msg, args...)))
+}
+
+func (l *zapLogger) With(fields ...Field) Logger {
+	zapFields := make([]zap.Field, 0, len(fields))
+	for _, field := range fields {
+		if value, ok := field.Value.(string); ok {
+			zapFields = append(zapFields, zap.String(field.Key, l.redact(value)))
+			continue
+		}
+		zapFields = append(zapFields, zap.Any(field.Key, field.Value))
+	}
+	return &zapLogger{logger: l.logger.With(zapFields...), emoji: l.emoji, redact: l.redact}
+}
+
+// message redacts msg and prefixes it with the emoji of its level in console format
+func (l *zapLogger) message(emoji, msg string) string {
+	if l.emoji {
+		msg = emoji + msg
+	}
+	return l.redact(msg)
 }
 
 var (
@@ -57,16 +81,16 @@ var (
 	zapLoggerInstance *zap.Logger
 )
 
-func setupZapLogger() *zapLogger {
+func setupZapLogger(o options) *zapLogger {
 	if zapLoggerInstance == nil {
 		once.Do(func() {
-			create()
+			create(o)
 		})
 	}
-	return &zapLogger{logger: zapLoggerInstance}
+	return &zapLogger{logger: zapLoggerInstance, emoji: o.format != JSONFormat, redact: o.redact}
 }
 
-func create() {
+func create(o options) {
 	consoleWriteSyncer := zapcore.AddSync(os.Stdout)
 
 	var encoder zapcore.Encoder
@@ -75,16 +99,36 @@ func create() {
 
 	var loggerOptions []zap.Option
 
-	encoderConfig = zap.NewDevelopmentEncoderConfig()
-	level = zap.DebugLevel
+	level = zapLevel(o.level)
 	loggerOptions = append(loggerOptions, zap.AddStacktrace(zapcore.ErrorLevel))
 
-	encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05.000")
-	encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-	encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	if o.format == JSONFormat {
+		encoderConfig = zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	} else {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05.000")
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
 
 	consoleCore := zapcore.NewCore(encoder, consoleWriteSyncer, level)
 	zapCore := zapcore.NewTee(consoleCore)
 
 	zapLoggerInstance = zap.New(zapCore, loggerOptions...)
 }
+
+// zapLevel converts a Level to the zap level
+func zapLevel(level Level) zapcore.Level {
+	switch level {
+	case InfoLevel:
+		return zapcore.InfoLevel
+	case WarnLevel:
+		return zapcore.WarnLevel
+	case ErrorLevel:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
@@ -0,0 +1,206 @@
+package redact
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// rule replaces the matches of a pattern with a placeholder naming its kind. When
+// keep is set the first submatch, e.g. "password: ", is kept in front of the
+// placeholder and the second, if any, after it. Rules with a secret check instead
+// match a key and its value, the first and second submatch, and only redact values
+// the check accepts.
+type rule struct {
+	kind    string
+	pattern *regexp.Regexp
+	keep    bool
+	secret  func(value string) bool
+}
+
+func (r rule) placeholder() string {
+	if r.kind == "" {
+		return "[REDACTED]"
+	}
+	return "[REDACTED:" + r.kind + "]"
+}
+
+func (r rule) replacement() string {
+	if r.keep {
+		return "${1}" + r.placeholder() + "${2}"
+	}
+	return r.placeholder()
+}
+
+// apply redacts the matches of the rule in s
+func (r rule) apply(s string) string {
+	if r.secret == nil {
+		return r.pattern.ReplaceAllString(s, r.replacement())
+	}
+	return r.pattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := r.pattern.FindStringSubmatch(match)
+		if len(groups) < 3 || !r.secret(groups[2]) {
+			return match
+		}
+		return groups[1] + r.placeholder()
+	})
+}
+
+// builtinRules match credentials commonly pasted into issues and committed to repositories, and email addresses
+var builtinRules = []rule{
+	{kind: "private-key", pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{kind: "github-token", pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{kind: "api-key", pattern: regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}`)},
+	{kind: "aws-key", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{kind: "google-key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{kind: "slack-token", pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{kind: "jwt", pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{kind: "token", pattern: regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9._~+/-]{16,}=*`), keep: true},
+	{kind: "password", pattern: regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+(@)`), keep: true},
+	{kind: "secret", pattern: regexp.MustCompile(`(?i)(\b(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|client[_-]?secret|private[_-]?key)["']?\s*[:=]\s*["']?)([^\s"',;\[]{6,})`), secret: isSecretValue},
+	{kind: "email", pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+}
+
+var (
+	// identifier matches names of variables, types and placeholders, e.g. "newPassword" or "string"
+	identifier = regexp.MustCompile(`^[A-Za-z_]+$`)
+	// selector matches field and package selectors, e.g. "config.apiKey" or "process.env.TOKEN"
+	selector = regexp.MustCompile(`^[A-Za-z_$][\w$]*(?:\.[A-Za-z_$][\w$]*)+$`)
+)
+
+// isSecretValue reports whether the value assigned to a secret-like key looks like a secret
+// rather than code computing it, so source files keep their meaning in prompts
+func isSecretValue(value string) bool {
+	switch {
+	case strings.ContainsAny(value, "()"):
+		// A call, e.g. os.Getenv("TOKEN")
+		return false
+	case strings.HasPrefix(value, "$"), strings.HasPrefix(value, "%"), strings.HasPrefix(value, "<"):
+		// A reference or placeholder, e.g. ${API_KEY}, %(password)s or <token>
+		return false
+	case selector.MatchString(value):
+		return false
+	case identifier.MatchString(value) && len(value) < 20:
+		return false
+	}
+	return true
+}
+
+// defaultSecretFiles are the names of files that typically hold credentials
+var defaultSecretFiles = []string{
+	".env", ".env.*", "*.env",
+	"*.pem", "*.key", "*.p12", "*.pfx", "*.jks", "*.keystore",
+	"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519",
+	".npmrc", ".pypirc", ".netrc", ".htpasswd", ".git-credentials",
+	"credentials", "credentials.*", "*credentials*.json", "service-account*.json",
+	"secret.*", "secrets.*", "*.secret", "*.secrets",
+	"*.tfstate", "*.tfvars", "kubeconfig",
+}
+
+// Redactor masks secrets and personal data in text, e.g. log lines and prompts
+// sent to AI providers, and recognizes files that should not leave the repository
+type Redactor struct {
+	rules       []rule
+	secretFiles []string
+
+	mu     sync.RWMutex
+	values []string
+}
+
+// Option configures a Redactor
+type Option func(*Redactor) error
+
+// Default returns a redactor with the built-in patterns and secret files
+func Default() *Redactor {
+	return &Redactor{rules: builtinRules, secretFiles: defaultSecretFiles}
+}
+
+// New creates a redactor with the built-in patterns and secret files and the given options
+func New(opts ...Option) (*Redactor, error) {
+	r := Default()
+	for _, opt := range opts {
+		if err := opt(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// WithPatterns adds regular expressions whose matches are redacted
+func WithPatterns(patterns ...string) Option {
+	return func(r *Redactor) error {
+		rules := make([]rule, 0, len(r.rules)+len(patterns))
+		rules = append(rules, r.rules...)
+		for _, pattern := range patterns {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+			}
+			rules = append(rules, rule{pattern: compiled})
+		}
+		r.rules = rules
+		return nil
+	}
+}
+
+// WithSecretFiles adds glob patterns of secret files. Patterns containing a "/"
+// are matched against the path, others against the file name.
+func WithSecretFiles(globs ...string) Option {
+	return func(r *Redactor) error {
+		for _, glob := range globs {
+			if _, err := path.Match(glob, ""); err != nil {
+				return fmt.Errorf("invalid secret file pattern %q: %w", glob, err)
+			}
+		}
+		r.secretFiles = append(append([]string{}, r.secretFiles...), globs...)
+		return nil
+	}
+}
+
+// Add redacts a known secret, e.g. an API key, wherever it appears
+func (r *Redactor) Add(value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values = append(r.values, value)
+}
+
+// Redact returns s with secrets and email addresses replaced by placeholders
+func (r *Redactor) Redact(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+
+	r.mu.RLock()
+	for _, value := range r.values {
+		s = strings.ReplaceAll(s, value, "[REDACTED]")
+	}
+	r.mu.RUnlock()
+
+	for _, rule := range r.rules {
+		s = rule.apply(s)
+	}
+	return s
+}
+
+// IsSecretFile reports whether the file at the repository path likely holds credentials
+func (r *Redactor) IsSecretFile(filePath string) bool {
+	if r == nil {
+		return false
+	}
+	name := path.Base(filePath)
+	for _, glob := range r.secretFiles {
+		target := name
+		if strings.Contains(glob, "/") {
+			target = filePath
+		}
+		if matched, _ := path.Match(glob, target); matched {
+			return true
+		}
+	}
+	return false
+}
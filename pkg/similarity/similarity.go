@@ -0,0 +1,99 @@
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// stopWords are common English words that carry no meaning for similarity scoring
+var stopWords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "but": {},
+	"by": {}, "can": {}, "do": {}, "does": {}, "for": {}, "from": {}, "has": {}, "have": {},
+	"how": {}, "i": {}, "if": {}, "in": {}, "into": {}, "is": {}, "it": {}, "its": {},
+	"me": {}, "my": {}, "no": {}, "not": {}, "of": {}, "on": {}, "or": {}, "so": {},
+	"that": {}, "the": {}, "then": {}, "there": {}, "this": {}, "to": {}, "was": {}, "we": {},
+	"what": {}, "when": {}, "where": {}, "which": {}, "while": {}, "with": {}, "would": {}, "you": {},
+}
+
+// Tokenize splits text into lower-cased terms, dropping punctuation, stop words and single characters
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var tokens []string
+	for _, field := range fields {
+		if len(field) < 2 {
+			continue
+		}
+		if _, ok := stopWords[field]; ok {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// Keywords returns up to max distinct terms of text, ordered by frequency and then first appearance
+func Keywords(text string, max int) []string {
+	tokens := Tokenize(text)
+
+	counts := make(map[string]int)
+	var order []string
+	for _, token := range tokens {
+		if counts[token] == 0 {
+			order = append(order, token)
+		}
+		counts[token]++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+
+	if len(order) > max {
+		order = order[:max]
+	}
+	return order
+}
+
+// Cosine returns the cosine similarity (0.0-1.0) of the term frequency vectors of a and b
+func Cosine(a, b string) float64 {
+	va := termFrequencies(a)
+	vb := termFrequencies(b)
+	if len(va) == 0 || len(vb) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for term, fa := range va {
+		normA += fa * fa
+		if fb, ok := vb[term]; ok {
+			dot += fa * fb
+		}
+	}
+	for _, fb := range vb {
+		normB += fb * fb
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// IssueScore scores two issues, weighting title similarity above body similarity
+func IssueScore(titleA, bodyA, titleB, bodyB string) float64 {
+	titleScore := Cosine(titleA, titleB)
+	if strings.TrimSpace(bodyA) == "" || strings.TrimSpace(bodyB) == "" {
+		return titleScore
+	}
+	return 0.6*titleScore + 0.4*Cosine(bodyA, bodyB)
+}
+
+func termFrequencies(text string) map[string]float64 {
+	frequencies := make(map[string]float64)
+	for _, token := range Tokenize(text) {
+		frequencies[token]++
+	}
+	return frequencies
+}
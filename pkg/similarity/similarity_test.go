@@ -0,0 +1,103 @@
+package similarity
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"The app crashes when I open it", []string{"app", "crashes", "open"}},
+		{"nil-pointer in config.Load()", []string{"nil", "pointer", "config", "load"}},
+		{"Error: EOF (exit code 2) on v1.2", []string{"error", "eof", "exit", "code", "v1"}},
+		{"Ünïcode Widgets 日本語", []string{"ünïcode", "widgets", "日本語"}},
+		{"a I x", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		max  int
+		want []string
+	}{
+		{"by frequency", "crash on save, crash on load, save fails", 10, []string{"crash", "save", "load", "fails"}},
+		{"ties keep first appearance", "widgets gadgets gizmos", 10, []string{"widgets", "gadgets", "gizmos"}},
+		{"limited", "crash crash save load", 2, []string{"crash", "save"}},
+		{"case folded", "Crash CRASH crash", 5, []string{"crash"}},
+		{"only stop words", "the and of", 5, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Keywords(tt.text, tt.max); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Keywords(%q, %d) = %q, want %q", tt.text, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"identical", "widgets vanish on resize", "widgets vanish on resize", 1},
+		{"order and case", "Widgets vanish", "vanish widgets", 1},
+		{"stop words only differ", "the widgets vanish", "widgets vanish when", 1},
+		{"disjoint", "widgets vanish", "login fails", 0},
+		{"half", "widgets vanish", "widgets flicker", 0.5},
+		{"repeated terms", "crash crash", "crash save", 1 / math.Sqrt2},
+		{"empty", "", "widgets", 0},
+		{"stop words only", "the of", "the of", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cosine(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Cosine(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got, reverse := Cosine(tt.a, tt.b), Cosine(tt.b, tt.a); math.Abs(got-reverse) > 1e-9 {
+				t.Errorf("Cosine is not symmetric: %v and %v", got, reverse)
+			}
+		})
+	}
+}
+
+func TestIssueScore(t *testing.T) {
+	tests := []struct {
+		name                         string
+		titleA, bodyA, titleB, bodyB string
+		want                         float64
+	}{
+		{"same issue", "Widgets vanish", "after resize", "Widgets vanish", "after resize", 1},
+		{"title weighs more", "Widgets vanish", "login fails", "Widgets vanish", "after resize", 0.6},
+		{"body alone", "Widgets vanish", "after resize", "Login fails", "after resize", 0.4},
+		{"missing body uses the title", "Widgets vanish", "", "Widgets vanish", "after resize", 1},
+		{"blank body uses the title", "Widgets vanish", "after resize", "Widgets flicker", " \n", 0.5},
+		{"unrelated", "Widgets vanish", "after resize", "Login fails", "with 500", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IssueScore(tt.titleA, tt.bodyA, tt.titleB, tt.bodyB); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("IssueScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}